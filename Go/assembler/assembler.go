@@ -0,0 +1,584 @@
+// Package assembler turns SM83 assembly, in the syntax produced by the cartridge
+// package or rgbasm, back into bytes.
+//
+// Supported directives are SECTION, ORG, DEF/EQU, db, dw, ds and INCBIN. Labels
+// starting with a . are local to the previous global label and the I/O registers
+// are predefined using their hardware.inc names.
+package assembler
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// Error is a problem with a specific line of the source
+type Error struct {
+	Line    int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Message)
+}
+
+// Assembler assembles source into a ROM image
+type Assembler struct {
+	// ReadFile loads files referenced by INCBIN, os.ReadFile when nil
+	ReadFile func(name string) ([]byte, error)
+}
+
+// Assemble assembles src using the default options
+func Assemble(src string) ([]byte, error) {
+	return (&Assembler{}).Assemble(src)
+}
+
+type sectionType int
+
+const (
+	SECTION_ROM0 sectionType = iota
+	SECTION_ROMX
+)
+
+type section struct {
+	name string
+	kind sectionType
+	bank int
+	//Address the next statement will be placed at
+	pc int
+}
+
+// offset converts an address within the section into an offset in the ROM image
+func (s *section) offset(address int) int {
+	if s.kind == SECTION_ROM0 {
+		return address
+	}
+	return s.bank*0x4000 + address - 0x4000
+}
+
+func (s *section) end() int {
+	if s.kind == SECTION_ROM0 {
+		return 0x4000
+	}
+	return 0x8000
+}
+
+type statement struct {
+	line    int
+	section *section
+	address int
+	size    int
+
+	//Set for instructions
+	encoding *encoding
+	operands []operand
+	//Set for db and dw, width is the number of bytes per expression
+	data  []dataItem
+	width int
+	//Set for INCBIN
+	binary []byte
+}
+
+// dataItem is either an expression or the bytes of a string
+type dataItem struct {
+	expr  expr
+	bytes []byte
+}
+
+// assembly is the state of a single call to Assemble
+type assembly struct {
+	*Assembler
+	sections   map[string]*section
+	current    *section
+	statements []*statement
+	symbols    map[string]int
+	line       int
+	//Last global label, used to qualify local labels
+	scope string
+}
+
+func (a *assembly) lookup(name string) (int, bool) {
+	if value, ok := a.symbols[name]; ok {
+		return value, ok
+	}
+	return cartridge.HardwareRegisterAddress(name)
+}
+
+func (a *assembly) pc() int {
+	if a.current == nil {
+		return 0
+	}
+	return a.current.pc
+}
+
+func (a *assembly) parseExpr(src string) (expr, error) {
+	return parseExpr(src, a.scope, a.pc())
+}
+
+// constant parses and evaluates an expression which has to be known on the first pass
+func (a *assembly) constant(src string) (int, error) {
+	e, err := a.parseExpr(src)
+	if err != nil {
+		return 0, err
+	}
+	return e.eval(a.lookup)
+}
+
+func (a *Assembler) Assemble(src string) ([]byte, error) {
+	asm := &assembly{
+		Assembler: a,
+		sections:  map[string]*section{},
+		symbols:   map[string]int{},
+	}
+
+	//First pass places every statement so that labels are known
+	for i, line := range strings.Split(src, "\n") {
+		asm.line = i + 1
+		if err := asm.parseLine(line); err != nil {
+			return nil, &Error{Line: asm.line, Message: err.Error()}
+		}
+	}
+
+	//Second pass encodes with every label resolved
+	size := 0
+	for _, s := range asm.statements {
+		size = max(size, s.section.offset(s.address)+s.size)
+	}
+	rom := make([]byte, size)
+	written := make([]bool, size)
+
+	for _, s := range asm.statements {
+		bytes, err := asm.emit(s)
+		if err != nil {
+			return nil, &Error{Line: s.line, Message: err.Error()}
+		}
+		offset := s.section.offset(s.address)
+		for i, b := range bytes {
+			if written[offset+i] {
+				return nil, &Error{Line: s.line, Message: fmt.Sprintf("overlaps previously written data at $%04X", s.address+i)}
+			}
+			written[offset+i] = true
+			rom[offset+i] = b
+		}
+	}
+
+	return rom, nil
+}
+
+func (a *assembly) emit(s *statement) ([]byte, error) {
+	switch {
+	case s.encoding != nil:
+		return a.encode(*s.encoding, s.operands, s.address)
+	case s.binary != nil:
+		return s.binary, nil
+	}
+
+	out := make([]byte, 0, s.size)
+	for _, item := range s.data {
+		if item.expr == nil {
+			out = append(out, item.bytes...)
+			continue
+		}
+		value, err := item.expr.eval(a.lookup)
+		if err != nil {
+			return nil, err
+		}
+		if s.width == 2 {
+			if value < -0x8000 || value > 0xFFFF {
+				return nil, fmt.Errorf("value %d does not fit in 16 bits", value)
+			}
+			out = append(out, byte(value), byte(value>>8))
+			continue
+		}
+		if value < -128 || value > 0xFF {
+			return nil, fmt.Errorf("value %d does not fit in 8 bits", value)
+		}
+		out = append(out, byte(value))
+	}
+	return out, nil
+}
+
+// place adds a statement of size bytes at the current position
+func (a *assembly) place(s *statement) error {
+	if a.current == nil {
+		return fmt.Errorf("code or data outside of a SECTION")
+	}
+	s.line = a.line
+	s.section = a.current
+	s.address = a.current.pc
+	if s.address+s.size > a.current.end() {
+		return fmt.Errorf("section %s grows past $%04X", a.current.name, a.current.end()-1)
+	}
+	a.current.pc += s.size
+	a.statements = append(a.statements, s)
+	return nil
+}
+
+func (a *assembly) define(name string, value int) error {
+	if _, ok := a.symbols[name]; ok {
+		return fmt.Errorf("%s is already defined", name)
+	}
+	a.symbols[name] = value
+	return nil
+}
+
+func (a *assembly) parseLine(line string) error {
+	line = strings.TrimSpace(stripComment(line))
+	if line == "" {
+		return nil
+	}
+
+	//Labels are an identifier followed by : or ::
+	if first, rest := cutSpace(line); strings.HasSuffix(first, ":") {
+		name := strings.TrimRight(first, ":")
+		if a.current == nil {
+			return fmt.Errorf("label %s outside of a SECTION", name)
+		}
+		if strings.HasPrefix(name, ".") {
+			if a.scope == "" {
+				return fmt.Errorf("local label %s without a global label", name)
+			}
+			name = a.scope + name
+		} else if global, _, ok := strings.Cut(name, "."); ok {
+			a.scope = global
+		} else {
+			a.scope = name
+		}
+		if err := a.define(name, a.current.pc); err != nil {
+			return err
+		}
+		line = strings.TrimSpace(rest)
+		if line == "" {
+			return nil
+		}
+	}
+
+	keyword, rest := cutSpace(line)
+
+	//name EQU value
+	if second, value := cutSpace(rest); strings.EqualFold(second, "EQU") {
+		return a.parseEqu(keyword, value)
+	}
+
+	switch strings.ToUpper(keyword) {
+	case "SECTION":
+		return a.parseSection(rest)
+	case "DEF":
+		name, value := cutSpace(rest)
+		directive, value := cutSpace(value)
+		if !strings.EqualFold(directive, "EQU") {
+			return fmt.Errorf("expected EQU after DEF %s", name)
+		}
+		return a.parseEqu(name, value)
+	case "ORG":
+		return a.parseOrg(rest)
+	case "DB":
+		return a.parseData(rest, 1)
+	case "DW":
+		return a.parseData(rest, 2)
+	case "DS":
+		return a.parseDS(rest)
+	case "INCBIN":
+		return a.parseIncbin(rest)
+	}
+
+	return a.parseInstruction(strings.ToLower(keyword), rest)
+}
+
+func (a *assembly) parseEqu(name string, value string) error {
+	v, err := a.constant(value)
+	if err != nil {
+		return err
+	}
+	return a.define(name, v)
+}
+
+// parseOrg moves the current position, starting a section for the address when there isn't one
+func (a *assembly) parseOrg(rest string) error {
+	address, err := a.constant(rest)
+	if err != nil {
+		return err
+	}
+	if address < 0 || address > 0x7FFF {
+		return fmt.Errorf("ORG $%04X is outside of ROM", address)
+	}
+
+	if a.current == nil {
+		s := &section{name: fmt.Sprintf("ORG $%04X", address), kind: SECTION_ROM0}
+		if address >= 0x4000 {
+			s.kind, s.bank = SECTION_ROMX, 1
+		}
+		a.sections[s.name] = s
+		a.current = s
+	}
+
+	start := 0x0000
+	if a.current.kind == SECTION_ROMX {
+		start = 0x4000
+	}
+	if address < start || address >= a.current.end() {
+		return fmt.Errorf("ORG $%04X is outside of section %s", address, a.current.name)
+	}
+	a.current.pc = address
+	return nil
+}
+
+// parseSection handles SECTION "name", ROM0[$addr] and SECTION "name", ROMX[$addr], BANK[n]
+func (a *assembly) parseSection(rest string) error {
+	args := splitArgs(rest)
+	if len(args) < 2 {
+		return fmt.Errorf("SECTION needs a name and type")
+	}
+	name, ok := unquote(args[0])
+	if !ok {
+		return fmt.Errorf("SECTION name must be a string")
+	}
+	if _, ok := a.sections[name]; ok {
+		return fmt.Errorf("section %s is already defined", name)
+	}
+
+	kind, address, err := a.bracketed(args[1])
+	if err != nil {
+		return err
+	}
+	s := &section{name: name}
+	switch strings.ToUpper(kind) {
+	case "ROM0":
+		s.kind, s.pc = SECTION_ROM0, 0x0000
+	case "ROMX":
+		s.kind, s.pc, s.bank = SECTION_ROMX, 0x4000, 1
+	default:
+		return fmt.Errorf("unsupported section type %s", kind)
+	}
+	if address >= 0 {
+		if address < s.pc || address >= s.end() {
+			return fmt.Errorf("address $%04X is outside of %s", address, kind)
+		}
+		s.pc = address
+	}
+
+	for _, arg := range args[2:] {
+		option, value, err := a.bracketed(arg)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(option, "BANK") || value < 0 {
+			return fmt.Errorf("unexpected %s in SECTION", arg)
+		}
+		if s.kind != SECTION_ROMX || value < 1 {
+			return fmt.Errorf("invalid bank %d for %s", value, kind)
+		}
+		s.bank = value
+	}
+
+	a.sections[name] = s
+	a.current = s
+	return nil
+}
+
+// bracketed splits NAME[expr] into the name and value of expr, -1 if there is no value
+func (a *assembly) bracketed(arg string) (string, int, error) {
+	name, inner, ok := strings.Cut(arg, "[")
+	if !ok {
+		return strings.TrimSpace(arg), -1, nil
+	}
+	inner, ok = strings.CutSuffix(strings.TrimSpace(inner), "]")
+	if !ok {
+		return "", 0, fmt.Errorf("missing ] in %s", arg)
+	}
+	value, err := a.constant(inner)
+	if err != nil {
+		return "", 0, err
+	}
+	return strings.TrimSpace(name), value, nil
+}
+
+// parseData handles db and dw, strings are only allowed in db
+func (a *assembly) parseData(rest string, width int) error {
+	s := &statement{width: width}
+	for _, arg := range splitArgs(rest) {
+		if str, ok := unquote(arg); ok && width == 1 {
+			s.data = append(s.data, dataItem{bytes: []byte(str)})
+			s.size += len(str)
+			continue
+		}
+		e, err := a.parseExpr(arg)
+		if err != nil {
+			return err
+		}
+		s.data = append(s.data, dataItem{expr: e})
+		s.size += width
+	}
+	return a.place(s)
+}
+
+// parseDS handles ds count with an optional fill byte
+func (a *assembly) parseDS(rest string) error {
+	args := splitArgs(rest)
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("ds needs a count and optional fill value")
+	}
+	count, err := a.constant(args[0])
+	if err != nil {
+		return err
+	}
+	if count < 0 {
+		return fmt.Errorf("ds count %d is negative", count)
+	}
+	fill := 0
+	if len(args) == 2 {
+		if fill, err = a.constant(args[1]); err != nil {
+			return err
+		}
+	}
+	if fill < -128 || fill > 0xFF {
+		return fmt.Errorf("value %d does not fit in 8 bits", fill)
+	}
+
+	data := make([]byte, count)
+	for i := range data {
+		data[i] = byte(fill)
+	}
+	return a.place(&statement{binary: data, size: count})
+}
+
+// parseIncbin handles INCBIN "file" with an optional start and length
+func (a *assembly) parseIncbin(rest string) error {
+	args := splitArgs(rest)
+	if len(args) == 0 {
+		return fmt.Errorf("INCBIN needs a file name")
+	}
+	name, ok := unquote(args[0])
+	if !ok {
+		return fmt.Errorf("INCBIN file name must be a string")
+	}
+
+	read := a.ReadFile
+	if read == nil {
+		read = os.ReadFile
+	}
+	data, err := read(name)
+	if err != nil {
+		return err
+	}
+
+	values := []int{}
+	for _, arg := range args[1:] {
+		v, err := a.constant(arg)
+		if err != nil {
+			return err
+		}
+		values = append(values, v)
+	}
+	start, length := 0, len(data)
+	if len(values) > 0 {
+		start, length = values[0], len(data)-values[0]
+	}
+	if len(values) > 1 {
+		length = values[1]
+	}
+	if start < 0 || length < 0 || start+length > len(data) {
+		return fmt.Errorf("INCBIN range is outside of %s", name)
+	}
+
+	return a.place(&statement{binary: data[start : start+length], size: length})
+}
+
+func (a *assembly) parseInstruction(mnemonic string, rest string) error {
+	if _, ok := encodings[mnemonic]; !ok {
+		return fmt.Errorf("unknown instruction %s", mnemonic)
+	}
+
+	operands := []operand{}
+	for _, arg := range splitArgs(rest) {
+		op, err := a.parseOperand(arg)
+		if err != nil {
+			return err
+		}
+		operands = append(operands, op)
+	}
+
+	e, ok := a.selectEncoding(mnemonic, operands)
+	//rgbasm spells ldh [c], a as ld [$ff00+c], a
+	if !ok && mnemonic == "ld" {
+		e, ok = a.selectEncoding("ldh", operands)
+	}
+	//rgbasm also accepts 8 bit arithmetic with the a left implied
+	if !ok && len(operands) == 1 && implicitA[mnemonic] {
+		operands = append([]operand{{kind: operandRegister, register: cartridge.REG_A}}, operands...)
+		e, ok = a.selectEncoding(mnemonic, operands)
+	}
+	if !ok {
+		return fmt.Errorf("invalid operands for %s: %s", mnemonic, rest)
+	}
+	return a.place(&statement{encoding: &e, operands: operands, size: e.length})
+}
+
+var implicitA = map[string]bool{
+	"add": true,
+	"adc": true,
+	"sub": true,
+	"sbc": true,
+	"and": true,
+	"xor": true,
+	"or":  true,
+	"cp":  true,
+}
+
+// cutSpace splits s around the first run of whitespace
+func cutSpace(s string) (string, string) {
+	i := strings.IndexAny(s, " \t")
+	if i < 0 {
+		return s, ""
+	}
+	return s[:i], strings.TrimSpace(s[i:])
+}
+
+// stripComment removes anything after a ; which isn't inside a string
+func stripComment(line string) string {
+	inString := false
+	for i, c := range line {
+		switch {
+		case c == '"':
+			inString = !inString
+		case c == ';' && !inString:
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitArgs splits on commas which aren't inside strings, brackets or parentheses
+func splitArgs(s string) []string {
+	args := []string{}
+	depth := 0
+	inString := false
+	start := 0
+	for i, c := range s {
+		switch {
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '[' || c == '(':
+			depth++
+		case c == ']' || c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			args = append(args, strings.TrimSpace(s[start:i]))
+			start = i + 1
+		}
+	}
+	if last := strings.TrimSpace(s[start:]); last != "" || len(args) > 0 {
+		args = append(args, last)
+	}
+	return args
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) >= 2 && s[0] == '"' && s[len(s)-1] == '"' {
+		return s[1 : len(s)-1], true
+	}
+	return "", false
+}
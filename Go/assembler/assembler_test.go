@@ -0,0 +1,130 @@
+package assembler
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// Every opcode the disassembler knows should assemble back from its own output
+func Test_AssembleDisassemblerOutput(t *testing.T) {
+	operandBytes := [][]byte{{0x00, 0x00}, {0x12, 0xC3}, {0x40, 0xFF}, {0xFE, 0x7F}, {0x80, 0x01}}
+
+	inputs := [][]byte{}
+	for op := 0; op <= 0xFF; op++ {
+		for _, operands := range operandBytes {
+			if op == 0xCB {
+				continue
+			}
+			inputs = append(inputs, append([]byte{byte(op)}, operands...))
+		}
+		inputs = append(inputs, []byte{0xCB, byte(op)})
+	}
+
+	for _, input := range inputs {
+		ins, err := cartridge.Decode(input, 0x150)
+		if err != nil {
+			t.Errorf("Unable to decode %x: %s", input, err)
+			continue
+		}
+		if ins.Opcode == cartridge.OP_INVALID {
+			continue
+		}
+
+		src := "SECTION \"test\", ROM0[$0150]\n" + ins.String()
+		output, err := Assemble(src)
+		if err != nil {
+			t.Errorf("Unable to assemble %q from %x: %s", ins.String(), input, err)
+			continue
+		}
+		if !bytes.Equal(output[0x150:], input[:ins.Length]) {
+			t.Errorf("Expected %q to assemble to %x but found %x", ins.String(), input[:ins.Length], output[0x150:])
+		}
+	}
+}
+
+func Test_AssembleDirectivesAndLabels(t *testing.T) {
+	src := `
+DEF COUNT EQU 3
+SECTION "main", ROM0[$0150]
+Main:
+	ld b, COUNT * 2 ; comment
+.loop:
+	dec b
+	jr nz, .loop
+	call Other
+	ld hl, Table
+	ld a, HIGH(Table) | 1
+	ldh [rLCDC], a
+	sub c
+	jp Main.loop
+Other:
+.loop:
+	ret
+Table:
+	db 1, "AB", -1
+	dw Table, $1234
+	ds 2, $AA
+
+	ORG $0200
+	db LOW(@)
+`
+	expected := []byte{
+		0x06, 0x06, // ld b, 6
+		0x05,       // dec b
+		0x20, 0xFD, // jr nz, $0152
+		0xCD, 0x63, 0x01, // call Other
+		0x21, 0x64, 0x01, // ld hl, Table
+		0x3E, 0x01, // ld a, 1
+		0xE0, 0x40, // ldh [rLCDC], a
+		0x91,             // sub a, c
+		0xC3, 0x52, 0x01, // jp Main.loop
+		0xC9,                   // ret
+		0x01, 0x41, 0x42, 0xFF, // db
+		0x64, 0x01, 0x34, 0x12, // dw
+		0xAA, 0xAA, // ds
+	}
+
+	output, err := Assemble(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(output[0x150:0x150+len(expected)], expected) {
+		t.Errorf("Expected %x but found %x", expected, output[0x150:0x150+len(expected)])
+	}
+	if len(output) != 0x201 || output[0x200] != 0x00 {
+		t.Errorf("Expected ORG to place a byte at $0200 but found length %x", len(output))
+	}
+}
+
+func Test_AssembleErrorsHaveLineNumbers(t *testing.T) {
+	table := []struct {
+		src  string
+		line int
+	}{
+		{"nop", 1},
+		{"SECTION \"a\", ROM0\n\tnop\n\tld q, 1", 3},
+		{"SECTION \"a\", ROM0\n\tjp Missing", 2},
+		{"SECTION \"a\", ROM0\n\tld a, 256", 2},
+		{"SECTION \"a\", ROM0\nL:\nL:", 3},
+		{"SECTION \"a\", ROM0\n\tjr Far\n\tds 200\nFar:", 2},
+		{"SECTION \"a\", ROM0\n\tinc nz", 2},
+		{"SECTION \"a\", ROM0\n\tinc z", 2},
+		{"SECTION \"a\", ROM0\n\tld z, 5", 2},
+		{"SECTION \"a\", ROM0\n\tdb 1 << -1", 2},
+		{"SECTION \"a\", ROM0\n\tdb 1 >> -1", 2},
+	}
+	for _, check := range table {
+		_, err := Assemble(check.src)
+		var asmErr *Error
+		if !errors.As(err, &asmErr) {
+			t.Errorf("Expected an assembler error for %q but found %v", check.src, err)
+			continue
+		}
+		if asmErr.Line != check.line {
+			t.Errorf("Expected error for %q on line %d but found %d: %s", check.src, check.line, asmErr.Line, asmErr)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package assembler
+
+import (
+	"fmt"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// encoding is one opcode along with the operand shapes it accepts, built from the
+// disassembler so that the two always agree on the instruction set
+type encoding struct {
+	opcode   []byte
+	operands []cartridge.Operand
+	length   int
+}
+
+var encodings = buildEncodings()
+
+func buildEncodings() map[string][]encoding {
+	table := map[string][]encoding{}
+	add := func(bytes []byte) {
+		ins, err := cartridge.Decode(bytes, 0)
+		if err != nil || ins.Opcode == cartridge.OP_INVALID {
+			return
+		}
+		prefix := 1
+		if bytes[0] == 0xCB {
+			prefix = 2
+		}
+		//stop has no operands but is followed by a padding byte
+		if len(ins.Operands) == 0 {
+			prefix = ins.Length
+		}
+		name := ins.Opcode.String()
+		table[name] = append(table[name], encoding{
+			opcode:   append([]byte(nil), bytes[:prefix]...),
+			operands: ins.Operands,
+			length:   ins.Length,
+		})
+	}
+
+	for op := 0; op <= 0xFF; op++ {
+		if op != 0xCB {
+			add([]byte{byte(op), 0, 0})
+		}
+	}
+	for op := 0; op <= 0xFF; op++ {
+		add([]byte{0xCB, byte(op)})
+	}
+	return table
+}
+
+// matches reports whether a parsed operand can be encoded as the operand of an opcode.
+// Bit indices and rst vectors are part of the opcode so their value has to be known.
+func (a *assembly) matches(parsed operand, want cartridge.Operand) bool {
+	switch want.Kind {
+	case cartridge.OPERAND_REG:
+		return parsed.kind == operandRegister && parsed.register == want.Register && parsed.indirect == want.Indirect
+	case cartridge.OPERAND_COND:
+		return parsed.isCond && parsed.cond == want.Cond
+	case cartridge.OPERAND_IMM8, cartridge.OPERAND_IMM16:
+		return parsed.kind == operandExpr && parsed.indirect == want.Indirect
+	case cartridge.OPERAND_OFFSET, cartridge.OPERAND_SIMM8:
+		return parsed.kind == operandExpr && !parsed.indirect
+	case cartridge.OPERAND_SP_OFFSET:
+		return parsed.kind == operandSPOffset
+	case cartridge.OPERAND_BIT, cartridge.OPERAND_VECTOR:
+		if parsed.kind != operandExpr || parsed.indirect {
+			return false
+		}
+		value, err := parsed.expr.eval(a.lookup)
+		return err == nil && value == want.Value
+	}
+	return false
+}
+
+// selectEncoding finds the opcode for a mnemonic and its operands
+func (a *assembly) selectEncoding(mnemonic string, operands []operand) (encoding, bool) {
+	for _, candidate := range encodings[mnemonic] {
+		if len(candidate.operands) != len(operands) {
+			continue
+		}
+		ok := true
+		for i := range operands {
+			if !a.matches(operands[i], candidate.operands[i]) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return candidate, true
+		}
+	}
+	return encoding{}, false
+}
+
+// encode writes the opcode and any immediate operands for an instruction at address
+func (a *assembly) encode(e encoding, operands []operand, address int) ([]byte, error) {
+	out := append(make([]byte, 0, e.length), e.opcode...)
+	for i, want := range e.operands {
+		parsed := operands[i]
+		if parsed.kind != operandExpr && parsed.kind != operandSPOffset {
+			continue
+		}
+		value, err := parsed.expr.eval(a.lookup)
+		if err != nil {
+			return nil, err
+		}
+
+		switch want.Kind {
+		case cartridge.OPERAND_IMM8:
+			//ldh takes an address in the 0xFF00 page
+			if want.Indirect && value >= 0xFF00 && value <= 0xFFFF {
+				value -= 0xFF00
+			}
+			if value < -128 || value > 0xFF {
+				return nil, fmt.Errorf("value %d does not fit in 8 bits", value)
+			}
+			out = append(out, byte(value))
+		case cartridge.OPERAND_SP_OFFSET, cartridge.OPERAND_SIMM8:
+			if value < -128 || value > 0xFF {
+				return nil, fmt.Errorf("offset %d does not fit in 8 bits", value)
+			}
+			out = append(out, byte(value))
+		case cartridge.OPERAND_IMM16:
+			if value < -0x8000 || value > 0xFFFF {
+				return nil, fmt.Errorf("value %d does not fit in 16 bits", value)
+			}
+			out = append(out, byte(value), byte(value>>8))
+		case cartridge.OPERAND_OFFSET:
+			//jr takes the absolute address of its target
+			relative := int(int16(value - (address + e.length)))
+			if relative < -128 || relative > 127 {
+				return nil, fmt.Errorf("jr target %d is out of range", value)
+			}
+			out = append(out, byte(relative))
+		}
+	}
+	return out, nil
+}
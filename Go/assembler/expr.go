@@ -0,0 +1,320 @@
+package assembler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// expr is a parsed expression which is evaluated once all labels are known
+type expr interface {
+	eval(symbols func(name string) (int, bool)) (int, error)
+}
+
+type number int
+
+func (n number) eval(func(string) (int, bool)) (int, error) {
+	return int(n), nil
+}
+
+type symbol string
+
+func (s symbol) eval(symbols func(string) (int, bool)) (int, error) {
+	value, ok := symbols(string(s))
+	if !ok {
+		return 0, fmt.Errorf("undefined symbol %s", string(s))
+	}
+	return value, nil
+}
+
+type unary struct {
+	op      string
+	operand expr
+}
+
+func (u unary) eval(symbols func(string) (int, bool)) (int, error) {
+	v, err := u.operand.eval(symbols)
+	if err != nil {
+		return 0, err
+	}
+	switch strings.ToUpper(u.op) {
+	case "-":
+		return -v, nil
+	case "+":
+		return v, nil
+	case "~":
+		return ^v, nil
+	case "!":
+		if v == 0 {
+			return 1, nil
+		}
+		return 0, nil
+	case "HIGH":
+		return (v >> 8) & 0xFF, nil
+	case "LOW":
+		return v & 0xFF, nil
+	}
+	return 0, fmt.Errorf("unknown operator %s", u.op)
+}
+
+type binary struct {
+	op          string
+	left, right expr
+}
+
+func (b binary) eval(symbols func(string) (int, bool)) (int, error) {
+	l, err := b.left.eval(symbols)
+	if err != nil {
+		return 0, err
+	}
+	r, err := b.right.eval(symbols)
+	if err != nil {
+		return 0, err
+	}
+	switch b.op {
+	case "+":
+		return l + r, nil
+	case "-":
+		return l - r, nil
+	case "*":
+		return l * r, nil
+	case "/", "%":
+		if r == 0 {
+			return 0, fmt.Errorf("division by zero")
+		}
+		if b.op == "/" {
+			return l / r, nil
+		}
+		return l % r, nil
+	case "&":
+		return l & r, nil
+	case "|":
+		return l | r, nil
+	case "^":
+		return l ^ r, nil
+	case "<<", ">>":
+		if r < 0 {
+			return 0, fmt.Errorf("negative shift amount %d", r)
+		}
+		if b.op == "<<" {
+			return l << r, nil
+		}
+		return l >> r, nil
+	}
+	return 0, fmt.Errorf("unknown operator %s", b.op)
+}
+
+// Binding power of each binary operator, higher binds tighter
+var precedence = map[string]int{
+	"|":  1,
+	"^":  2,
+	"&":  3,
+	"<<": 4,
+	">>": 4,
+	"+":  5,
+	"-":  5,
+	"*":  6,
+	"/":  6,
+	"%":  6,
+}
+
+// Functions taking a single argument
+var functions = map[string]bool{
+	"HIGH": true,
+	"LOW":  true,
+}
+
+type exprParser struct {
+	tokens []string
+	pos    int
+	//Global label that local .labels belong to
+	scope string
+	//Value of @, the address of the current statement
+	pc int
+}
+
+// parseExpr parses an expression of numbers, symbols and operators. Local labels
+// are qualified with scope and @ is replaced with pc.
+func parseExpr(src string, scope string, pc int) (expr, error) {
+	tokens, err := tokenizeExpr(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("missing expression")
+	}
+	p := &exprParser{tokens: tokens, scope: scope, pc: pc}
+	e, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos != len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %s in expression", p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+func (p *exprParser) peek() string {
+	if p.pos >= len(p.tokens) {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) parse(minPrecedence int) (expr, error) {
+	left, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op := p.peek()
+		prec, ok := precedence[op]
+		if !ok || prec <= minPrecedence {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parse(prec)
+		if err != nil {
+			return nil, err
+		}
+		left = binary{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) primary() (expr, error) {
+	token := p.peek()
+	if token == "" {
+		return nil, fmt.Errorf("unexpected end of expression")
+	}
+	p.pos++
+
+	switch {
+	case token == "-" || token == "+" || token == "~" || token == "!":
+		operand, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: token, operand: operand}, nil
+	case token == "(":
+		return p.parenthesised()
+	case token == "@":
+		return number(p.pc), nil
+	case functions[strings.ToUpper(token)] && p.peek() == "(":
+		p.pos++
+		operand, err := p.parenthesised()
+		if err != nil {
+			return nil, err
+		}
+		return unary{op: token, operand: operand}, nil
+	case strings.HasPrefix(token, "."):
+		if p.scope == "" {
+			return nil, fmt.Errorf("local label %s without a global label", token)
+		}
+		return symbol(p.scope + token), nil
+	case isIdentStart(token[0]):
+		return symbol(token), nil
+	case strings.HasPrefix(token, "'"):
+		return parseChar(token)
+	}
+	return parseNumber(token)
+}
+
+// parenthesised parses the rest of an expression after an opening (
+func (p *exprParser) parenthesised() (expr, error) {
+	e, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != ")" {
+		return nil, fmt.Errorf("missing )")
+	}
+	p.pos++
+	return e, nil
+}
+
+// parseNumber reads a number in rgbasm notation, $ for hex and % for binary
+func parseNumber(token string) (expr, error) {
+	base := 10
+	digits := token
+	switch {
+	case strings.HasPrefix(token, "$"):
+		base, digits = 16, token[1:]
+	case strings.HasPrefix(token, "%"):
+		base, digits = 2, token[1:]
+	case strings.HasPrefix(token, "0x"), strings.HasPrefix(token, "0X"):
+		base, digits = 16, token[2:]
+	case strings.HasPrefix(token, "0b"), strings.HasPrefix(token, "0B"):
+		base, digits = 2, token[2:]
+	}
+	value, err := strconv.ParseInt(strings.ReplaceAll(digits, "_", ""), base, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %s", token)
+	}
+	return number(value), nil
+}
+
+func parseChar(token string) (expr, error) {
+	value, err := strconv.Unquote(token)
+	if err != nil || len(value) != 1 {
+		return nil, fmt.Errorf("invalid character %s", token)
+	}
+	return number(value[0]), nil
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || c == '.' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || (c >= '0' && c <= '9') || c == '#' || c == '@'
+}
+
+func tokenizeExpr(src string) ([]string, error) {
+	tokens := []string{}
+	//Whether the previous token ends an operand, which makes % modulo instead of binary
+	afterOperand := func() bool {
+		if len(tokens) == 0 {
+			return false
+		}
+		last := tokens[len(tokens)-1]
+		_, isOperator := precedence[last]
+		return last == ")" || (!isOperator && !strings.ContainsAny(last, "(~!"))
+	}
+
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t':
+			i++
+		case isIdentStart(c):
+			start := i
+			for i < len(src) && isIdentChar(src[i]) {
+				i++
+			}
+			tokens = append(tokens, src[start:i])
+		case c == '$' || (c >= '0' && c <= '9') || (c == '%' && !afterOperand()):
+			start := i
+			i++
+			for i < len(src) && isIdentChar(src[i]) {
+				i++
+			}
+			tokens = append(tokens, src[start:i])
+		case c == '\'':
+			end := strings.IndexByte(src[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated character")
+			}
+			tokens = append(tokens, src[i:i+end+2])
+			i += end + 2
+		case strings.HasPrefix(src[i:], "<<"), strings.HasPrefix(src[i:], ">>"):
+			tokens = append(tokens, src[i:i+2])
+			i += 2
+		case strings.ContainsRune("+-*/%&|^~!()@", rune(c)):
+			tokens = append(tokens, string(c))
+			i++
+		default:
+			return nil, fmt.Errorf("unexpected character %q in expression", c)
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,95 @@
+package assembler
+
+import (
+	"strings"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+type operandKind int
+
+const (
+	operandRegister operandKind = iota
+	//nz, z and nc, which are only conditions
+	operandCondition
+	operandExpr
+	operandSPOffset
+)
+
+// operand is a parsed instruction operand, indirect when it was wrapped in []
+type operand struct {
+	kind     operandKind
+	register cartridge.Register
+	//c is both a register and a condition
+	isCond   bool
+	cond     cartridge.Condition
+	expr     expr
+	indirect bool
+}
+
+var registerNames = map[string]cartridge.Register{
+	"a":   cartridge.REG_A,
+	"b":   cartridge.REG_B,
+	"c":   cartridge.REG_C,
+	"d":   cartridge.REG_D,
+	"e":   cartridge.REG_E,
+	"h":   cartridge.REG_H,
+	"l":   cartridge.REG_L,
+	"af":  cartridge.REG_AF,
+	"bc":  cartridge.REG_BC,
+	"de":  cartridge.REG_DE,
+	"hl":  cartridge.REG_HL,
+	"sp":  cartridge.REG_SP,
+	"hl+": cartridge.REG_HLI,
+	"hli": cartridge.REG_HLI,
+	"hl-": cartridge.REG_HLD,
+	"hld": cartridge.REG_HLD,
+}
+
+var conditionNames = map[string]cartridge.Condition{
+	"nz": cartridge.COND_NZ,
+	"z":  cartridge.COND_Z,
+	"nc": cartridge.COND_NC,
+	"c":  cartridge.COND_C,
+}
+
+func (a *assembly) parseOperand(arg string) (operand, error) {
+	indirect := false
+	if strings.HasPrefix(arg, "[") && strings.HasSuffix(arg, "]") {
+		indirect = true
+		arg = strings.TrimSpace(arg[1 : len(arg)-1])
+	}
+	lower := strings.ToLower(arg)
+
+	//[$ff00+c] is the long form of [c]
+	if indirect && strings.ReplaceAll(lower, " ", "") == "$ff00+c" {
+		lower = "c"
+	}
+
+	reg, isReg := registerNames[lower]
+	cond, isCond := conditionNames[lower]
+	if isReg {
+		return operand{kind: operandRegister, register: reg, isCond: isCond && !indirect, cond: cond, indirect: indirect}, nil
+	}
+	if isCond && !indirect {
+		return operand{kind: operandCondition, isCond: true, cond: cond}, nil
+	}
+
+	//sp + e8 and sp - e8
+	if offset, ok := strings.CutPrefix(lower, "sp"); ok && !indirect {
+		offset = strings.TrimSpace(offset)
+		if strings.HasPrefix(offset, "+") || strings.HasPrefix(offset, "-") {
+			e, err := a.parseExpr(offset)
+			if err != nil {
+				return operand{}, err
+			}
+			return operand{kind: operandSPOffset, expr: e}, nil
+		}
+	}
+
+	e, err := a.parseExpr(arg)
+	if err != nil {
+		return operand{}, err
+	}
+	return operand{kind: operandExpr, expr: e, indirect: indirect}, nil
+}
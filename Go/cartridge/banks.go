@@ -0,0 +1,93 @@
+package cartridge
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ROM is mapped into the CPU address space as a fixed bank 0 at 0x0000-0x3FFF
+// and a switchable bank at 0x4000-0x7FFF
+const (
+	BANK_SIZE         = 0x4000
+	SWITCHABLE_START  = 0x4000
+	SWITCHABLE_END    = 0x7FFF
+	BANK_SELECT_START = 0x2000
+	BANK_SELECT_END   = 0x3FFF
+)
+
+// Location is an address as seen by the CPU along with the ROM bank mapped in at the time
+type Location struct {
+	Bank    int
+	Address int
+}
+
+// OffsetLocation converts a file offset into the bank and address it is mapped to
+func OffsetLocation(offset int) Location {
+	bank := offset / BANK_SIZE
+	if bank == 0 {
+		return Location{Bank: 0, Address: offset}
+	}
+	return Location{Bank: bank, Address: SWITCHABLE_START + offset%BANK_SIZE}
+}
+
+// Offset converts the location back into a file offset, returning false for
+// addresses outside of ROM or switchable addresses in bank 0
+func (l Location) Offset() (int, bool) {
+	switch {
+	case l.Address < 0 || l.Address > SWITCHABLE_END:
+		return 0, false
+	case l.Address < SWITCHABLE_START:
+		return l.Address, true
+	case l.Bank < 1:
+		return 0, false
+	}
+	return l.Bank*BANK_SIZE + l.Address - SWITCHABLE_START, true
+}
+
+func (l Location) String() string {
+	if l.Bank < 0 {
+		return fmt.Sprintf("??:%04X", l.Address)
+	}
+	return fmt.Sprintf("%02X:%04X", l.Bank, l.Address)
+}
+
+// ParseLocation reads a location written in hex as bank:address, the format used by
+// listings and symbol files. Without a bank, addresses in 0x0000-0x3FFF are in bank 0
+// and any other address has a bank of -1.
+func ParseLocation(s string) (Location, error) {
+	bank := -1
+	address := s
+	if b, a, ok := strings.Cut(s, ":"); ok {
+		value, err := strconv.ParseUint(b, 16, 16)
+		if err != nil {
+			return Location{}, fmt.Errorf("invalid bank in %q", s)
+		}
+		bank, address = int(value), a
+	}
+
+	address = strings.TrimPrefix(address, "$")
+	address = strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	value, err := strconv.ParseUint(address, 16, 16)
+	if err != nil {
+		return Location{}, fmt.Errorf("invalid address in %q", s)
+	}
+	if bank < 0 && value < SWITCHABLE_START {
+		bank = 0
+	}
+	return Location{Bank: bank, Address: int(value)}, nil
+}
+
+// resolveAddress maps an address referenced by code onto a location. Addresses in
+// 0x4000-0x7FFF resolve against bank which is -1 when the mapped bank isn't known.
+func resolveAddress(address int, bank int) (Location, bool) {
+	switch {
+	case address < 0 || address > SWITCHABLE_END:
+		return Location{}, false
+	case address < SWITCHABLE_START:
+		return Location{Bank: 0, Address: address}, true
+	case bank < 1:
+		return Location{}, false
+	}
+	return Location{Bank: bank, Address: address}, true
+}
@@ -4,27 +4,67 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 )
 
 type Cartridge struct {
 	Title            string
 	ManufacturerCode string //TODO: Need to check how this can be empty/null
-	newLicenseeCode  []byte //Need an example where these are set to check encoding
-	oldLicenseeCode  byte
-	cartridgeType    byte
-	romSize          int
-	instructions     []string
+	Header           *Header
+	instructions     []Instruction
+	kinds            []ByteKind
+	partial          int
+	targets          map[int]Location
+	labels           map[Location]string
+	names            map[string]Location
+	symbols          map[Location]string
+	format           Formatter
+	xrefs            xrefIndex
+	rom              []byte
 }
 
-func New(bytes []byte) (*Cartridge, error) {
-	err := Validate(bytes)
+// Option configures how New disassembles a cartridge
+type Option func(*options)
+
+type options struct {
+	symbols map[Location]string
+	format  Formatter
+}
+
+// WithSymbols names locations in the disassembly, replacing any generated labels.
+// Named ROM locations are also disassembled as entry points and named RAM
+// locations replace the addresses of memory operands.
+func WithSymbols(symbols map[Location]string) Option {
+	return func(o *options) {
+		o.symbols = symbols
+	}
+}
+
+// WithFormatter sets how String, Listing, Format and the JSON output write instructions,
+// in place of DefaultFormatter
+func WithFormatter(f Formatter) Option {
+	return func(o *options) {
+		o.format = f
+	}
+}
+
+func New(bytes []byte, opts ...Option) (*Cartridge, error) {
+	o := options{format: DefaultFormatter()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	header, err := ParseHeader(bytes)
 	if err != nil {
 		return nil, err
 	}
 
-	jmpBytes := make([]byte, 2)
-	binary.LittleEndian.PutUint16(jmpBytes, 0x150)
+	//Patched ROMs and homebrew often have bad checksums so only the logo is required
+	if err := Validate(bytes); errors.Is(err, ErrLogoMismatch) {
+		return nil, err
+	}
+
 	title := string(bytes[TITLE_START : TITLE_END+1])
 	manCode := bytesToRunesToString(bytes[MANUFACTUTURER_CODE_START : MANUFACTUTURER_CODE_END+1])
 	//Older Cartridges had this as part of title, the -1 is due to Manufacturer Code being at
@@ -34,25 +74,34 @@ func New(bytes []byte) (*Cartridge, error) {
 		manCode = "NONE"
 	}
 
-	// if bytes[0x0100] == OP_NOP && bytes[0x0101] == 0b11000011 && bytes[0x0102] == jmpBytes[0] && bytes[0x0103] == jmpBytes[1] {
-	// 	fmt.Println("Standard jump to 150 at entrypoint")
-	// }
-
 	cart := &Cartridge{
 		Title:            title,
-		newLicenseeCode:  bytes[NEW_LICENSEE_CODE_START : NEW_LICENSEE_CODE_END+1],
-		oldLicenseeCode:  bytes[OLD_LICENSEE_CODE],
-		cartridgeType:    bytes[CARTRIDGE_TYPE],
-		romSize:          int(bytes[ROM_SIZE]), //Could calculate direct to save recalculation each time
 		ManufacturerCode: manCode,
+		Header:           header,
+		symbols:          o.symbols,
+		format:           o.format,
+		rom:              bytes,
 	}
 
-	for i := 0x100; i < len(bytes); {
-		str, bytesUsed := dissassembleNextBytes(bytes[i:])
-		formatted := fmt.Sprintf("%s", str)
-		i = i + bytesUsed
-		cart.instructions = append(cart.instructions, formatted)
+	//Named ROM locations are traced as extra entry points
+	entries := []Location{}
+	for loc := range o.symbols {
+		entries = append(entries, loc)
 	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, _ := entries[i].Offset()
+		b, _ := entries[j].Offset()
+		return a < b
+	})
+
+	p := disassemble(bytes, entries...)
+	cart.instructions, cart.kinds, cart.targets, cart.partial = p.instructions, p.kinds, p.targets, p.partial
+	cart.labels = generateLabels(p)
+	for loc, name := range o.symbols {
+		cart.labels[loc] = name
+	}
+	cart.names = indexLabels(cart.labels)
+	cart.xrefs = buildXrefs(cart.instructions, cart.targets)
 
 	return cart, nil
 }
@@ -75,58 +124,152 @@ func (c *Cartridge) String() string {
 	builder.WriteString(fmt.Sprint(c.RomSize()))
 	builder.WriteRune('\n')
 
+	builder.WriteString("Ram Size: ")
+	builder.WriteString(fmt.Sprint(c.Header.RAMSize))
+	builder.WriteRune('\n')
+
 	builder.WriteString("Manufacturer Code: ")
 	builder.WriteString(c.ManufacturerCode)
 	builder.WriteRune('\n')
 
-	for _, s := range c.instructions {
-		str := fmt.Sprintf("%s \n", s)
-		builder.WriteString(str)
-	}
+	builder.WriteString(c.Listing(0, len(c.rom)))
 
 	return builder.String()
 }
 
-func (c *Cartridge) License() string {
-	if c.oldLicenseeCode != 0x33 {
-		value, ok := oldLicenseeCodeMap[c.oldLicenseeCode]
-		if !ok {
-			return "UNKNOWN"
+// Listing renders the instructions which start at file offsets from start up to but not including end,
+// followed by the bytes of an instruction the ROM ends part way through as data
+func (c *Cartridge) Listing(start, end int) string {
+	var builder strings.Builder
+	f := c.formatter()
+	previous := -1
+	for _, ins := range c.instructions {
+		if ins.Offset < start || ins.Offset >= end {
+			continue
 		}
-		return value
+		//Separate runs of code which aren't contiguous in the ROM
+		if previous >= 0 && previous != ins.Offset {
+			builder.WriteRune('\n')
+		}
+		previous = ins.Offset + ins.Length
+		if label, ok := c.labels[ins.Location()]; ok {
+			builder.WriteString(label)
+			builder.WriteString(":\n")
+		}
+		builder.WriteString(f.line(ins))
+		builder.WriteRune('\n')
 	}
 
-	value, ok := newLicenseeCodeMap[string(c.newLicenseeCode)]
-	if !ok {
-		return "UNKNOWN NEW"
+	//The ROM ends part way through the last instruction reached, so its bytes are shown as data
+	if c.partial >= start && c.partial < end {
+		if previous >= 0 && previous != c.partial {
+			builder.WriteRune('\n')
+		}
+		loc := OffsetLocation(c.partial)
+		if label, ok := c.labels[loc]; ok {
+			builder.WriteString(label)
+			builder.WriteString(":\n")
+		}
+		builder.WriteString(f.dataLine(loc, c.rom[c.partial:]))
+		builder.WriteRune('\n')
 	}
-	return value
+
+	return builder.String()
+}
+
+// Instructions returns the instructions reachable from the entry points in the order they appear in the ROM
+func (c *Cartridge) Instructions() []Instruction {
+	return c.instructions
+}
+
+// Label returns the name given to a location which is the target of a branch
+func (c *Cartridge) Label(loc Location) (string, bool) {
+	name, ok := c.labels[loc]
+	return name, ok
+}
+
+// Lookup finds the location with the given label, the first by bank and address when a
+// symbol file uses the same name more than once
+func (c *Cartridge) Lookup(name string) (Location, bool) {
+	loc, ok := c.names[name]
+	return loc, ok
+}
+
+// formatter is the configured formatter with the labels and symbols of the program
+func (c *Cartridge) formatter() Formatter {
+	return c.format.withContext(c.labels, c.targets, c.symbols)
+}
+
+// Format renders an instruction as it appears in the listing, with labels in place of branch targets
+func (c *Cartridge) Format(ins Instruction) string {
+	return c.formatter().Instruction(ins)
+}
+
+// Line renders an instruction with the columns selected by the formatter, as it appears in Listing
+func (c *Cartridge) Line(ins Instruction) string {
+	return c.formatter().line(ins)
+}
+
+// Target returns the resolved location a jp, jr, call or rst instruction branches to
+func (c *Cartridge) Target(ins Instruction) (Location, bool) {
+	loc, ok := c.targets[ins.Offset]
+	return loc, ok
+}
+
+// Kind reports whether the byte at offset was found to be code, data or was never reached
+func (c *Cartridge) Kind(offset int) ByteKind {
+	if offset < 0 || offset >= len(c.kinds) {
+		return BYTE_UNKNOWN
+	}
+	return c.kinds[offset]
+}
+
+func (c *Cartridge) License() string {
+	return c.Header.Licensee()
 }
 
 func (c *Cartridge) RomSize() int {
-	kib := 1024 * 32
-	return kib * (1 << c.romSize)
+	return c.Header.ROMSize
 }
 
 func (c *Cartridge) Type() string {
-	value, ok := cartridgeTypeMap[c.cartridgeType]
-	if !ok {
-		return "Unknown cartridge type"
-	}
-	return value
+	return c.Header.CartridgeType.Name()
 }
 
+var (
+	ErrLogoMismatch   = errors.New("nintendo logo does not match")
+	ErrHeaderChecksum = errors.New("header checksum does not match")
+	ErrGlobalChecksum = errors.New("global checksum does not match")
+)
+
+// Validate checks the logo and both checksums, returning every failure joined together.
+// Use errors.Is with ErrLogoMismatch, ErrHeaderChecksum and ErrGlobalChecksum to tell them apart.
 func Validate(bytes []byte) error {
-	validateNintendoLogo(bytes)
+	if err := checkHeaderSize(bytes); err != nil {
+		return err
+	}
+
+	var errs []error
 	if !validateNintendoLogo(bytes) {
-		return errors.New("unable to verfy nintendo logo, please check the carteidge you are using")
+		errs = append(errs, ErrLogoMismatch)
+	}
+	if expected, actual := computeHeaderChecksum(bytes), bytes[HEADER_CHECKSUM]; expected != actual {
+		errs = append(errs, fmt.Errorf("%w: expected %02X, got %02X", ErrHeaderChecksum, expected, actual))
 	}
-	return nil
+	//Only the header checksum is checked by the boot ROM, the global checksum is often wrong
+	expected := computeGlobalChecksum(bytes)
+	if actual := binary.BigEndian.Uint16(bytes[GLOBAL_CHECKSUM_START:]); expected != actual {
+		errs = append(errs, fmt.Errorf("%w: expected %04X, got %04X", ErrGlobalChecksum, expected, actual))
+	}
+	return errors.Join(errs...)
 }
 
 func validateNintendoLogo(bytes []byte) bool {
 	//TODO: Only validate half if this fails and check due to newer cartridges
-	slice := bytes[0x104:0x0134]
+	if len(bytes) <= NINTENDO_LOGO_END {
+		return false
+	}
+	slice := bytes[NINTENDO_LOGO_START : NINTENDO_LOGO_END+1]
 	expected := []byte{0xCE, 0xED, 0x66, 0x66, 0xCC, 0x0D, 0x00, 0x0B, 0x03, 0x73, 0x00, 0x83, 0x00, 0x0C, 0x00, 0x0D,
 		0x00, 0x08, 0x11, 0x1F, 0x88, 0x89, 0x00, 0x0E, 0xDC, 0xCC, 0x6E, 0xE6, 0xDD, 0xDD, 0xD9, 0x99,
 		0xBB, 0xBB, 0x67, 0x63, 0x6E, 0x0E, 0xEC, 0xCC, 0xDD, 0xDC, 0x99, 0x9F, 0xBB, 0xB9, 0x33, 0x3E}
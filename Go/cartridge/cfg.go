@@ -0,0 +1,306 @@
+package cartridge
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// EdgeKind is how control passes from one basic block to another
+type EdgeKind int
+
+const (
+	EDGE_TAKEN EdgeKind = iota
+	EDGE_FALLTHROUGH
+	EDGE_CALL
+)
+
+var edgeKindNames = [...]string{
+	EDGE_TAKEN:       "taken",
+	EDGE_FALLTHROUGH: "fallthrough",
+	EDGE_CALL:        "call",
+}
+
+func (k EdgeKind) String() string {
+	if k < 0 || int(k) >= len(edgeKindNames) {
+		return fmt.Sprintf("EdgeKind(%d)", int(k))
+	}
+	return edgeKindNames[k]
+}
+
+type Edge struct {
+	Kind EdgeKind
+	From Location
+	To   Location
+}
+
+// BasicBlock is a run of instructions which is only entered at the start and only
+// left at the end. Blocks end at every jump, call and return.
+type BasicBlock struct {
+	Start        Location
+	Instructions []Instruction
+	Edges        []Edge
+}
+
+// Function is the code reachable from a call target or entry vector without
+// following calls. Blocks shared between functions appear in each of them.
+type Function struct {
+	Entry  Location
+	Name   string
+	Blocks []*BasicBlock
+}
+
+// CFG is the control flow graph of the decoded program, or of a single function
+type CFG struct {
+	Blocks    []*BasicBlock
+	Functions []*Function
+	cart      *Cartridge
+	//Every function of the program, shared with subgraphs so that calls can be timed
+	byEntry map[Location]*Function
+	timings map[Location]Timing
+	//Functions which can call each other share a number, see recursionComponents
+	recursion map[Location]int
+}
+
+// endsBlock reports whether control can leave an instruction other than by falling through
+func endsBlock(ins Instruction) bool {
+	switch ins.Opcode {
+	case OP_JP, OP_JR, OP_CALL, OP_RST, OP_RET, OP_RETI, OP_INVALID:
+		return true
+	}
+	return false
+}
+
+// CFG splits the decoded instructions into basic blocks and functions
+func (c *Cartridge) CFG() *CFG {
+	entries := map[Location]bool{}
+	for _, v := range rstVectors {
+		entries[Location{Bank: 0, Address: v}] = true
+	}
+	for v := range vectorLabels {
+		entries[Location{Bank: 0, Address: v}] = true
+	}
+	for loc := range c.xrefs.functions {
+		entries[loc] = true
+	}
+
+	leaders := map[int]bool{}
+	for loc := range entries {
+		if offset, ok := loc.Offset(); ok {
+			leaders[offset] = true
+		}
+	}
+	for _, target := range c.targets {
+		if offset, ok := target.Offset(); ok {
+			leaders[offset] = true
+		}
+	}
+
+	g := &CFG{cart: c, byEntry: map[Location]*Function{}, timings: map[Location]Timing{}}
+	blocks := map[Location]*BasicBlock{}
+	var current *BasicBlock
+	for i, ins := range c.instructions {
+		contiguous := i > 0 && c.instructions[i-1].Offset+c.instructions[i-1].Length == ins.Offset
+		if current == nil || leaders[ins.Offset] || !contiguous || endsBlock(c.instructions[i-1]) {
+			current = &BasicBlock{Start: ins.Location()}
+			g.Blocks = append(g.Blocks, current)
+			blocks[current.Start] = current
+		}
+		current.Instructions = append(current.Instructions, ins)
+	}
+
+	for i, block := range g.Blocks {
+		last := block.Instructions[len(block.Instructions)-1]
+		if target, ok := c.targets[last.Offset]; ok && blocks[target] != nil {
+			kind := EDGE_TAKEN
+			if last.Opcode == OP_CALL || last.Opcode == OP_RST {
+				kind = EDGE_CALL
+			}
+			block.Edges = append(block.Edges, Edge{Kind: kind, From: block.Start, To: target})
+		}
+		if i+1 < len(g.Blocks) && fallsThrough(last) {
+			next := g.Blocks[i+1]
+			if first := next.Instructions[0]; first.Offset == last.Offset+last.Length {
+				block.Edges = append(block.Edges, Edge{Kind: EDGE_FALLTHROUGH, From: block.Start, To: next.Start})
+			}
+		}
+	}
+
+	for _, block := range g.Blocks {
+		if !entries[block.Start] {
+			continue
+		}
+		f := &Function{Entry: block.Start, Name: c.labels[block.Start]}
+		if f.Name == "" {
+			f.Name = block.Start.String()
+		}
+
+		//Follow everything but calls, stopping at the start of other functions
+		visited := map[Location]bool{}
+		queue := []Location{block.Start}
+		for len(queue) > 0 {
+			loc := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			if visited[loc] {
+				continue
+			}
+			visited[loc] = true
+			b := blocks[loc]
+			f.Blocks = append(f.Blocks, b)
+			for _, edge := range b.Edges {
+				if edge.Kind != EDGE_CALL && !entries[edge.To] {
+					queue = append(queue, edge.To)
+				}
+			}
+		}
+		sort.Slice(f.Blocks[1:], func(i, j int) bool {
+			return f.Blocks[i+1].Instructions[0].Offset < f.Blocks[j+1].Instructions[0].Offset
+		})
+		g.Functions = append(g.Functions, f)
+		g.byEntry[f.Entry] = f
+	}
+	g.recursion = g.recursionComponents()
+	return g
+}
+
+// Function returns the function starting at loc
+func (g *CFG) Function(entry Location) (*Function, bool) {
+	for _, f := range g.Functions {
+		if f.Entry == entry {
+			return f, true
+		}
+	}
+	return nil, false
+}
+
+// Subgraph returns the graph of a single function, edges leaving it are kept
+func (g *CFG) Subgraph(f *Function) *CFG {
+	return &CFG{Blocks: f.Blocks, Functions: []*Function{f}, cart: g.cart, byEntry: g.byEntry, timings: g.timings, recursion: g.recursion}
+}
+
+func dotEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s)
+}
+
+// DOT renders the graph in the Graphviz DOT language, with each function in its own cluster
+func (g *CFG) DOT() string {
+	var builder strings.Builder
+	builder.WriteString("digraph cfg {\n")
+	builder.WriteString("\tnode [shape=box fontname=\"monospace\"];\n")
+
+	//Blocks shared between functions are drawn in the first function that has them
+	drawn := map[Location]bool{}
+	for _, f := range g.Functions {
+		fmt.Fprintf(&builder, "\tsubgraph \"cluster_%s\" {\n", f.Entry)
+		fmt.Fprintf(&builder, "\t\tlabel=\"%s\";\n", dotEscape(f.Name))
+		for _, b := range f.Blocks {
+			if drawn[b.Start] {
+				continue
+			}
+			drawn[b.Start] = true
+			fmt.Fprintf(&builder, "\t\t\"%s\" [label=\"%s\"];\n", b.Start, g.blockText(b))
+		}
+		builder.WriteString("\t}\n")
+	}
+	for _, b := range g.Blocks {
+		if !drawn[b.Start] {
+			drawn[b.Start] = true
+			fmt.Fprintf(&builder, "\t\"%s\" [label=\"%s\"];\n", b.Start, g.blockText(b))
+		}
+	}
+
+	for _, b := range g.Blocks {
+		for _, edge := range b.Edges {
+			//Targets outside of the graph are shown by name only
+			if !drawn[edge.To] {
+				drawn[edge.To] = true
+				fmt.Fprintf(&builder, "\t\"%s\" [label=\"%s\" style=dashed];\n", edge.To, dotEscape(g.name(edge.To)))
+			}
+			style := ""
+			if edge.Kind == EDGE_CALL {
+				style = " style=dashed"
+			}
+			fmt.Fprintf(&builder, "\t\"%s\" -> \"%s\" [label=\"%s\"%s];\n", edge.From, edge.To, edge.Kind, style)
+		}
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+func (g *CFG) name(loc Location) string {
+	if name, ok := g.cart.labels[loc]; ok {
+		return name
+	}
+	return loc.String()
+}
+
+// blockText is the escaped listing of a block with DOT left justified line breaks
+func (g *CFG) blockText(b *BasicBlock) string {
+	var builder strings.Builder
+	if name, ok := g.cart.labels[b.Start]; ok {
+		builder.WriteString(dotEscape(name))
+		builder.WriteString(":\\l")
+	}
+	for _, ins := range b.Instructions {
+		fmt.Fprintf(&builder, "%s %s\\l", ins.Location(), dotEscape(g.cart.Format(ins)))
+	}
+	return builder.String()
+}
+
+type edgeJSON struct {
+	Kind string `json:"kind"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+type blockInstructionJSON struct {
+	Location string `json:"location"`
+	Bytes    string `json:"bytes"`
+	Text     string `json:"text"`
+}
+
+type blockJSON struct {
+	Start        string                 `json:"start"`
+	Label        string                 `json:"label,omitempty"`
+	Instructions []blockInstructionJSON `json:"instructions"`
+	Edges        []edgeJSON             `json:"edges"`
+}
+
+type functionJSON struct {
+	Entry  string   `json:"entry"`
+	Name   string   `json:"name"`
+	Blocks []string `json:"blocks"`
+}
+
+type cfgJSON struct {
+	Functions []functionJSON `json:"functions"`
+	Blocks    []blockJSON    `json:"blocks"`
+}
+
+// MarshalJSON encodes the functions by the start of their blocks, and each block with its instructions and edges
+func (g *CFG) MarshalJSON() ([]byte, error) {
+	out := cfgJSON{Functions: []functionJSON{}, Blocks: []blockJSON{}}
+	for _, f := range g.Functions {
+		j := functionJSON{Entry: f.Entry.String(), Name: f.Name, Blocks: []string{}}
+		for _, b := range f.Blocks {
+			j.Blocks = append(j.Blocks, b.Start.String())
+		}
+		out.Functions = append(out.Functions, j)
+	}
+	for _, b := range g.Blocks {
+		j := blockJSON{Start: b.Start.String(), Label: g.cart.labels[b.Start], Edges: []edgeJSON{}}
+		for _, ins := range b.Instructions {
+			j.Instructions = append(j.Instructions, blockInstructionJSON{
+				Location: ins.Location().String(),
+				Bytes:    fmt.Sprintf("%X", ins.Bytes),
+				Text:     g.cart.Format(ins),
+			})
+		}
+		for _, edge := range b.Edges {
+			j.Edges = append(j.Edges, edgeJSON{Kind: edge.Kind.String(), From: edge.From.String(), To: edge.To.String()})
+		}
+		out.Blocks = append(out.Blocks, j)
+	}
+	return json.Marshal(out)
+}
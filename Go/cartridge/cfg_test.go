@@ -0,0 +1,108 @@
+package cartridge
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_CFG(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0xCD, 0x60, 0x01, // call 0x160
+			0x18, 0xFB}, // jr 0x150
+		0x160: {0x3C, // inc a
+			0x20, 0x02, // jr nz, 0x165
+			0x3D,              // dec a
+			0xC8,              // ret z
+			0x3C,              // inc a
+			0xC3, 0x70, 0x01}, // jp 0x170
+		0x170: {0xCD, 0x60, 0x01, 0xC9}, // call 0x160, ret
+	})
+	c, err := New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := c.CFG()
+
+	f, ok := g.Function(Location{Bank: 0, Address: 0x160})
+	if !ok {
+		t.Fatalf("Expected a function at 0x160")
+	}
+	starts := []int{}
+	for _, b := range f.Blocks {
+		starts = append(starts, b.Start.Address)
+	}
+	//ret z ends the block at 0x163, 0x165 is both a jump target and the fall through
+	//and 0x170 is only jumped to so is part of the same function
+	expected := []int{0x160, 0x163, 0x165, 0x170, 0x173}
+	if len(starts) != len(expected) {
+		t.Fatalf("Expected blocks at %x but found %x", expected, starts)
+	}
+	for i := range expected {
+		if starts[i] != expected[i] {
+			t.Errorf("Expected blocks at %x but found %x", expected, starts)
+		}
+	}
+
+	//The 0xFF filling the rest of the ROM decodes as rst $38 so only the code from 0x150 is checked
+	edges := map[string]EdgeKind{}
+	for _, b := range g.Blocks {
+		if b.Start.Address < 0x150 {
+			continue
+		}
+		for _, e := range b.Edges {
+			edges[e.From.String()+">"+e.To.String()] = e.Kind
+		}
+	}
+	checks := map[string]EdgeKind{
+		"00:0150>00:0160": EDGE_CALL,
+		"00:0150>00:0153": EDGE_FALLTHROUGH,
+		"00:0153>00:0150": EDGE_TAKEN,
+		"00:0160>00:0165": EDGE_TAKEN,
+		"00:0160>00:0163": EDGE_FALLTHROUGH,
+		"00:0163>00:0165": EDGE_FALLTHROUGH,
+		"00:0165>00:0170": EDGE_TAKEN,
+		"00:0170>00:0160": EDGE_CALL,
+		"00:0170>00:0173": EDGE_FALLTHROUGH,
+	}
+	for edge, kind := range checks {
+		if found, ok := edges[edge]; !ok || found != kind {
+			t.Errorf("Expected a %s edge %s", kind, edge)
+		}
+	}
+	if len(edges) != len(checks) {
+		t.Errorf("Expected %d edges but found %v", len(checks), edges)
+	}
+
+	dot := g.Subgraph(f).DOT()
+	for _, want := range []string{
+		"\"00:0160\" [label=\"sub_0160:\\l00:0160 inc a\\l00:0161 jr nz, jr_0_0165\\l\"];",
+		"\"00:0170\" -> \"00:0160\" [label=\"call\" style=dashed];",
+		"\"00:0165\" -> \"00:0170\" [label=\"taken\"];",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("Expected %s in\n%s", want, dot)
+		}
+	}
+
+	var decoded cfgJSON
+	data, err := json.Marshal(g.Subgraph(f))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Functions) != 1 || len(decoded.Blocks) != 5 || decoded.Blocks[2].Edges[0].Kind != "taken" {
+		t.Errorf("Unexpected json %s", data)
+	}
+}
+
+func Test_EdgeKindString(t *testing.T) {
+	for kind, expected := range map[EdgeKind]string{EDGE_CALL: "call", -1: "EdgeKind(-1)", 3: "EdgeKind(3)"} {
+		if kind.String() != expected {
+			t.Errorf("Expected %s but found %s", expected, kind)
+		}
+	}
+}
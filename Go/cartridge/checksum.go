@@ -0,0 +1,36 @@
+package cartridge
+
+import "encoding/binary"
+
+// computeHeaderChecksum computes the checksum the boot ROM checks over 0x0134-0x014C,
+// bytes must hold the whole header
+func computeHeaderChecksum(bytes []byte) byte {
+	var checksum byte
+	for _, b := range bytes[TITLE_START:HEADER_CHECKSUM] {
+		checksum = checksum - b - 1
+	}
+	return checksum
+}
+
+// computeGlobalChecksum computes the sum of every byte in the ROM except the global checksum itself,
+// bytes must hold the whole header
+func computeGlobalChecksum(bytes []byte) uint16 {
+	var checksum uint16
+	for i, b := range bytes {
+		if i != GLOBAL_CHECKSUM_START && i != GLOBAL_CHECKSUM_END {
+			checksum += uint16(b)
+		}
+	}
+	return checksum
+}
+
+// FixChecksums rewrites the header and global checksums in place to match the contents of the ROM
+func FixChecksums(bytes []byte) error {
+	if err := checkHeaderSize(bytes); err != nil {
+		return err
+	}
+	//The header checksum is part of the global checksum so has to be fixed first
+	bytes[HEADER_CHECKSUM] = computeHeaderChecksum(bytes)
+	binary.BigEndian.PutUint16(bytes[GLOBAL_CHECKSUM_START:], computeGlobalChecksum(bytes))
+	return nil
+}
@@ -0,0 +1,57 @@
+package cartridge
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func Test_ValidateExampleChecksums(t *testing.T) {
+	rom, err := os.ReadFile("../example/example.gb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(rom); err != nil {
+		t.Errorf("expected example rom to be valid, got %v", err)
+	}
+}
+
+func Test_ValidateReportsEachFailure(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{})
+	if err := FixChecksums(rom); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(rom); err != nil {
+		t.Fatalf("expected fixed rom to be valid, got %v", err)
+	}
+
+	//Changing code outside the header only breaks the global checksum
+	rom[0x150] ^= 0xFF
+	err := Validate(rom)
+	if !errors.Is(err, ErrGlobalChecksum) || errors.Is(err, ErrHeaderChecksum) || errors.Is(err, ErrLogoMismatch) {
+		t.Errorf("expected only a global checksum error, got %v", err)
+	}
+	if _, err := New(rom); err != nil {
+		t.Errorf("expected a bad checksum to be accepted, got %v", err)
+	}
+
+	rom[TITLE_START] = 'X'
+	rom[NINTENDO_LOGO_START] = 0
+	err = Validate(rom)
+	for _, want := range []error{ErrLogoMismatch, ErrHeaderChecksum, ErrGlobalChecksum} {
+		if !errors.Is(err, want) {
+			t.Errorf("expected %v in %v", want, err)
+		}
+	}
+	if _, err := New(rom); !errors.Is(err, ErrLogoMismatch) {
+		t.Errorf("expected a bad logo to be rejected, got %v", err)
+	}
+
+	rom[NINTENDO_LOGO_START] = nintendoLogo[0]
+	if err := FixChecksums(rom); err != nil {
+		t.Fatal(err)
+	}
+	if err := Validate(rom); err != nil {
+		t.Errorf("expected fixed rom to be valid, got %v", err)
+	}
+}
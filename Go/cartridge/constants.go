@@ -5,7 +5,7 @@ const (
 	ENTRY_POINT_START         = 0x0100
 	ENTRY_POINT_END           = 0x0103
 	NINTENDO_LOGO_START       = 0x0104
-	NINTENDO_LOGO_END         = 0x0113
+	NINTENDO_LOGO_END         = 0x0133
 	TITLE_START               = 0x0134
 	TITLE_END                 = 0x0143
 	MANUFACTUTURER_CODE_START = 0x013F
@@ -0,0 +1,261 @@
+package cartridge
+
+import "sort"
+
+// ByteKind records what the disassembler determined a byte of the ROM to be
+type ByteKind byte
+
+const (
+	BYTE_UNKNOWN ByteKind = iota
+	BYTE_CODE
+	BYTE_DATA
+)
+
+func (k ByteKind) String() string {
+	switch k {
+	case BYTE_CODE:
+		return "code"
+	case BYTE_DATA:
+		return "data"
+	}
+	return "unknown"
+}
+
+// Execution starts at the entry point, the rst vectors and interrupt vectors can
+// also be reached without an explicit jump so they are treated as entry points
+var rstVectors = []int{0x00, 0x08, 0x10, 0x18, 0x20, 0x28, 0x30, 0x38}
+var interruptVectors = []int{0x40, 0x48, 0x50, 0x58, 0x60}
+
+// program is the result of disassembling a ROM
+type program struct {
+	instructions []Instruction
+	kinds        []ByteKind
+	//Resolved jump, call and rst targets keyed by the offset of the branching instruction
+	targets map[int]Location
+	//Offset of the instruction the ROM ends part way through, -1 if there isn't one
+	partial int
+}
+
+type disassembler struct {
+	rom          []byte
+	kinds        []ByteKind
+	instructions map[int]Instruction
+	targets      map[int]Location
+	queue        []trace
+	banks        int
+	partial      int
+}
+
+// trace is a pending run of code along with the bank believed to be mapped
+// into 0x4000-0x7FFF when it is reached, -1 if unknown
+type trace struct {
+	offset       int
+	selectedBank int
+}
+
+// disassemble follows control flow from the entry points, only decoding bytes
+// which can actually be reached by the CPU. Extra entry points are traced last
+// so they never take precedence over the real control flow.
+func disassemble(rom []byte, entries ...Location) program {
+	d := &disassembler{
+		rom:          rom,
+		kinds:        make([]ByteKind, len(rom)),
+		instructions: map[int]Instruction{},
+		targets:      map[int]Location{},
+		banks:        (len(rom) + BANK_SIZE - 1) / BANK_SIZE,
+		partial:      -1,
+	}
+
+	//The logo and header fields are never executed
+	for i := NINTENDO_LOGO_START; i <= GLOBAL_CHECKSUM_END && i < len(rom); i++ {
+		d.kinds[i] = BYTE_DATA
+	}
+
+	//Without an MBC the second bank is always mapped in
+	selected := -1
+	if d.banks <= 2 {
+		selected = 1
+	}
+
+	for _, loc := range entries {
+		offset, ok := loc.Offset()
+		if !ok {
+			continue
+		}
+		//Code in a switchable bank runs with its own bank selected
+		bank := selected
+		if loc.Address >= SWITCHABLE_START {
+			bank = loc.Bank
+		}
+		d.enqueue(offset, bank)
+	}
+	d.enqueue(ENTRY_POINT_START, selected)
+	for _, v := range rstVectors {
+		d.enqueue(v, selected)
+	}
+	for _, v := range interruptVectors {
+		d.enqueue(v, selected)
+	}
+
+	for len(d.queue) > 0 {
+		next := d.queue[len(d.queue)-1]
+		d.queue = d.queue[:len(d.queue)-1]
+		d.trace(next)
+	}
+
+	instructions := make([]Instruction, 0, len(d.instructions))
+	for _, ins := range d.instructions {
+		instructions = append(instructions, ins)
+	}
+	sort.Slice(instructions, func(i, j int) bool {
+		return instructions[i].Offset < instructions[j].Offset
+	})
+
+	return program{instructions: instructions, kinds: d.kinds, targets: d.targets, partial: d.partial}
+}
+
+func (d *disassembler) enqueue(offset int, selectedBank int) {
+	if offset < 0 || offset >= len(d.rom) {
+		return
+	}
+	d.queue = append(d.queue, trace{offset: offset, selectedBank: selectedBank})
+}
+
+// trace decodes a straight line run of instructions until control flow leaves
+// it, queueing any branch targets found on the way
+func (d *disassembler) trace(t trace) {
+	offset := t.offset
+	selected := t.selectedBank
+	//Value last loaded into a, used to spot writes selecting a new ROM bank
+	aValue := -1
+
+	for offset < len(d.rom) {
+		if d.kinds[offset] != BYTE_UNKNOWN {
+			//Either already decoded, or jumping into the middle of an instruction or data
+			return
+		}
+
+		ins, err := Decode(d.rom[offset:], offset)
+		if err != nil {
+			//The ROM ends part way through an instruction so the rest can only be data
+			d.partial = offset
+			for i := offset; i < len(d.rom) && d.kinds[i] == BYTE_UNKNOWN; i++ {
+				d.kinds[i] = BYTE_DATA
+			}
+			return
+		}
+		if !d.contiguous(offset, ins.Length, selected) {
+			return
+		}
+		for i := offset; i < offset+ins.Length; i++ {
+			if d.kinds[i] != BYTE_UNKNOWN {
+				return
+			}
+		}
+		for i := offset; i < offset+ins.Length; i++ {
+			d.kinds[i] = BYTE_CODE
+		}
+		d.instructions[offset] = ins
+
+		//Code running from a switchable bank can only reach its own bank
+		bank := selected
+		if ins.Bank > 0 {
+			bank = ins.Bank
+		}
+		if address, ok := branchTarget(ins); ok {
+			if loc, ok := resolveAddress(address, bank); ok && loc.Bank < d.banks {
+				d.targets[offset] = loc
+				target, _ := loc.Offset()
+				d.enqueue(target, selected)
+			}
+		}
+		if !fallsThrough(ins) {
+			return
+		}
+
+		selected, aValue = d.trackBankSwitch(ins, selected, aValue)
+		offset += ins.Length
+	}
+}
+
+// contiguous reports whether the bytes at offset are next to each other in the
+// address space, which is only true across a bank boundary from bank 0 into bank 1
+func (d *disassembler) contiguous(offset int, length int, selected int) bool {
+	first := offset / BANK_SIZE
+	last := (offset + length - 1) / BANK_SIZE
+	return first == last || (first == 0 && last == 1 && selected == 1)
+}
+
+// trackBankSwitch follows the common `ld a, n` then `ld [$2000], a` pattern used
+// to switch ROM banks so that later branches into 0x4000-0x7FFF can be resolved
+func (d *disassembler) trackBankSwitch(ins Instruction, selected int, aValue int) (int, int) {
+	switch {
+	//ld a, imm8
+	case ins.Bytes[0] == 0x3E:
+		return selected, int(ins.Bytes[1])
+	//xor a, a
+	case ins.Bytes[0] == 0xAF:
+		return selected, 0
+	//ld [imm16], a
+	case ins.Bytes[0] == 0xEA:
+		address := int(ins.Bytes[1]) | int(ins.Bytes[2])<<8
+		if address >= BANK_SELECT_START && address <= BANK_SELECT_END && aValue >= 0 {
+			bank := aValue % d.banks
+			//Selecting bank 0 maps bank 1 on most controllers
+			if bank == 0 {
+				bank = 1
+			}
+			return bank, aValue
+		}
+		return selected, aValue
+	case ins.Opcode == OP_CALL, ins.Opcode == OP_RST:
+		return selected, -1
+	//Rotates, cpl and daa change a without naming it
+	case ins.Opcode == OP_RLCA, ins.Opcode == OP_RRCA, ins.Opcode == OP_RLA, ins.Opcode == OP_RRA,
+		ins.Opcode == OP_CPL, ins.Opcode == OP_DAA:
+		return selected, -1
+	case ins.Opcode == OP_CP:
+		return selected, aValue
+	case len(ins.Operands) > 0 && ins.Operands[0].Kind == OPERAND_REG && ins.Operands[0].Register == REG_A && !ins.Operands[0].Indirect:
+		return selected, -1
+	case ins.Opcode == OP_POP && ins.Operands[0].Register == REG_AF:
+		return selected, -1
+	}
+	return selected, aValue
+}
+
+// branchTarget returns the address that a jp, jr, call or rst can transfer control to.
+// jp hl is not included as the target cannot be known statically
+func branchTarget(ins Instruction) (int, bool) {
+	if len(ins.Operands) == 0 {
+		return 0, false
+	}
+	last := ins.Operands[len(ins.Operands)-1]
+
+	switch ins.Opcode {
+	case OP_JP, OP_CALL:
+		if last.Kind == OPERAND_IMM16 {
+			return last.Value, true
+		}
+	case OP_JR:
+		return ins.Address + ins.Length + last.Value, true
+	case OP_RST:
+		return last.Value, true
+	}
+	return 0, false
+}
+
+// fallsThrough reports whether execution can continue with the next instruction in memory
+func fallsThrough(ins Instruction) bool {
+	switch ins.Opcode {
+	case OP_JP, OP_JR, OP_RET:
+		return isConditional(ins)
+	case OP_RETI, OP_INVALID:
+		return false
+	}
+	return true
+}
+
+func isConditional(ins Instruction) bool {
+	return len(ins.Operands) > 0 && ins.Operands[0].Kind == OPERAND_COND
+}
@@ -0,0 +1,211 @@
+package cartridge
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+var nintendoLogo = []byte{0xCE, 0xED, 0x66, 0x66, 0xCC, 0x0D, 0x00, 0x0B, 0x03, 0x73, 0x00, 0x83, 0x00, 0x0C, 0x00, 0x0D,
+	0x00, 0x08, 0x11, 0x1F, 0x88, 0x89, 0x00, 0x0E, 0xDC, 0xCC, 0x6E, 0xE6, 0xDD, 0xDD, 0xD9, 0x99,
+	0xBB, 0xBB, 0x67, 0x63, 0x6E, 0x0E, 0xEC, 0xCC, 0xDD, 0xDC, 0x99, 0x9F, 0xBB, 0xB9, 0x33, 0x3E}
+
+// newTestROM builds a ROM of the given size filled with 0xFF with a valid logo and code placed at the given offsets
+func newTestROM(size int, code map[int][]byte) []byte {
+	rom := make([]byte, size)
+	for i := range rom {
+		rom[i] = 0xFF
+	}
+	for i := NINTENDO_LOGO_START; i <= GLOBAL_CHECKSUM_END; i++ {
+		rom[i] = 0x00
+	}
+	copy(rom[NINTENDO_LOGO_START:], nintendoLogo)
+	for offset, bytes := range code {
+		copy(rom[offset:], bytes)
+	}
+	return rom
+}
+
+func Test_DisassembleFollowsControlFlow(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x00:  {0xC9},                   // ret
+		0x08:  {0xC9},                   // ret
+		0x10:  {0xC9},                   // ret
+		0x18:  {0xC9},                   // ret
+		0x20:  {0xC9},                   // ret
+		0x28:  {0xC9},                   // ret
+		0x30:  {0xC9},                   // ret
+		0x38:  {0xC9},                   // ret
+		0x40:  {0xD9},                   // reti
+		0x48:  {0xD9},                   // reti
+		0x50:  {0xD9},                   // reti
+		0x58:  {0xD9},                   // reti
+		0x60:  {0xD9},                   // reti
+		0x100: {0x00, 0xC3, 0x50, 0x01}, // nop, jp 0x150
+		0x150: {0xCD, 0x60, 0x01, // call 0x160
+			0x20, 0xFB, // jr nz, 0x150
+			0x18, 0x05, // jr 0x15C
+			0x01, 0x02, 0x03, 0x04, 0x05, // data skipped over
+			0x76,        // halt
+			0x18, 0xFD}, // jr 0x15C
+		0x160: {0xC9}, // ret
+	})
+
+	p := disassemble(rom)
+	instructions, kinds := p.instructions, p.kinds
+
+	offsets := []int{}
+	for _, ins := range instructions {
+		if ins.Offset >= 0x100 {
+			offsets = append(offsets, ins.Offset)
+		}
+	}
+	expected := []int{0x100, 0x101, 0x150, 0x153, 0x155, 0x15C, 0x15D, 0x160}
+	if len(offsets) != len(expected) {
+		t.Fatalf("Expected instructions at %x but found %x", expected, offsets)
+	}
+	for i := range expected {
+		if offsets[i] != expected[i] {
+			t.Errorf("Expected instruction at %x but found %x", expected[i], offsets[i])
+		}
+	}
+
+	checks := []struct {
+		offset int
+		kind   ByteKind
+	}{
+		{0x00, BYTE_CODE},
+		{0x01, BYTE_UNKNOWN},
+		{0x102, BYTE_CODE},
+		{0x104, BYTE_DATA},
+		{0x14F, BYTE_DATA},
+		{0x157, BYTE_UNKNOWN},
+		{0x15B, BYTE_UNKNOWN},
+		{0x15C, BYTE_CODE},
+		{0x15F, BYTE_UNKNOWN},
+	}
+	for _, check := range checks {
+		if kinds[check.offset] != check.kind {
+			t.Errorf("Expected byte %x to be %s but found %s", check.offset, check.kind, kinds[check.offset])
+		}
+	}
+}
+
+func Test_DisassembleResolvesSwitchableBanks(t *testing.T) {
+	rom := newTestROM(0x10000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0x3E, 0x02, // ld a, 2
+			0xEA, 0x00, 0x20, // ld [0x2000], a
+			0xCD, 0x00, 0x40, // call 0x4000
+			0x18, 0xFE}, // jr 0x158
+		0x8000: {0x18, 0x00, // jr 0x4002
+			0xC3, 0x10, 0x40}, // jp 0x4010
+		0x8010: {0xC9}, // ret
+	})
+
+	p := disassemble(rom)
+	instructions, kinds := p.instructions, p.kinds
+
+	found := map[Location]bool{}
+	for _, ins := range instructions {
+		found[ins.Location()] = true
+	}
+	for _, loc := range []Location{{2, 0x4000}, {2, 0x4002}, {2, 0x4010}} {
+		if !found[loc] {
+			t.Errorf("Expected instruction at %s", loc)
+		}
+	}
+	if kinds[0x4000] != BYTE_UNKNOWN || kinds[0xC000] != BYTE_UNKNOWN {
+		t.Errorf("Expected banks 1 and 3 to be unreached")
+	}
+
+	offset, ok := Location{Bank: 2, Address: 0x4010}.Offset()
+	if !ok || offset != 0x8010 {
+		t.Errorf("Expected 02:4010 to be at offset 8010 but found %x", offset)
+	}
+	if loc := OffsetLocation(0x8010); loc.String() != "02:4010" {
+		t.Errorf("Expected offset 8010 to be 02:4010 but found %s", loc)
+	}
+}
+
+func Test_DisassembleForgetsAOnceChanged(t *testing.T) {
+	rom := newTestROM(0x10000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0x3E, 0x01, // ld a, 1
+			0x07,             // rlca
+			0xEA, 0x00, 0x20, // ld [0x2000], a
+			0xC3, 0x00, 0x40}, // jp 0x4000
+		0x4000: {0xC9}, // ret
+	})
+
+	p := disassemble(rom)
+	for _, ins := range p.instructions {
+		if ins.Location() == (Location{Bank: 1, Address: 0x4000}) {
+			t.Errorf("Expected the bank after rlca to be unknown but found an instruction at %s", ins.Location())
+		}
+	}
+}
+
+func Test_LabelsAndSymbolicTargets(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0xCD, 0x60, 0x01, // call 0x160
+			0x18, 0xFB}, // jr 0x150
+		0x160: {0xC9}, // ret
+	})
+
+	c, err := New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	labels := map[Location]string{
+		{0, 0x0100}: "entry",
+		{0, 0x0150}: "jp_0_0150",
+		{0, 0x0160}: "sub_0160",
+		{0, 0x0038}: "rst_38",
+	}
+	for loc, expected := range labels {
+		if name, ok := c.Label(loc); !ok || name != expected {
+			t.Errorf("Expected label at %s to be %s but found %s", loc, expected, name)
+		}
+	}
+
+	listing := c.String()
+	for _, line := range []string{"sub_0160:\n00:0160 ret\n", "00:0150 call sub_0160\n", "00:0153 jr jp_0_0150\n"} {
+		if !strings.Contains(listing, line) {
+			t.Errorf("Expected listing to contain %q", line)
+		}
+	}
+}
+
+func Test_DisassembleTruncatedROM(t *testing.T) {
+	//A ROM cut off in the middle of the operand of its last instruction
+	rom := newTestROM(0x152, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0x00, 0xC3},       // nop, jp without its operand
+	})
+	c, err := New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Kind(0x150) != BYTE_CODE || c.Kind(0x151) != BYTE_DATA {
+		t.Errorf("Expected the partial instruction to be data but found %s and %s", c.Kind(0x150), c.Kind(0x151))
+	}
+	if listing := c.Listing(0x150, len(rom)); listing != "jp_0_0150:\n00:0150 nop\n00:0151 db 195\n" {
+		t.Errorf("Expected the partial instruction to be listed as data but found %q", listing)
+	}
+	hex, _ := New(rom, WithFormatter(Formatter{Address: true, Bytes: true, Base: BASE_DOLLAR}))
+	if listing := hex.Listing(0x151, len(rom)); listing != "00:0151 C3 db $C3\n" {
+		t.Errorf("Expected the partial instruction to be listed with its bytes but found %q", listing)
+	}
+	if main := string(c.RGBDS("truncated").Files["truncated.asm"]); !strings.HasSuffix(main, "\tnop\n\tdb $C3\n") {
+		t.Errorf("Expected the RGBDS export to end with the partial instruction as data but found %q", main[len(main)-20:])
+	}
+
+	for _, size := range []int{0, 0x100, 0x133, GLOBAL_CHECKSUM_END} {
+		if _, err := New(rom[:size]); !errors.Is(err, ErrFileTooSmall) {
+			t.Errorf("Expected a %d byte file to be too small but found %v", size, err)
+		}
+	}
+}
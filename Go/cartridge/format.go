@@ -0,0 +1,356 @@
+package cartridge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NumberBase selects how numbers are written
+type NumberBase int
+
+const (
+	BASE_DECIMAL NumberBase = iota // 31
+	BASE_DOLLAR                    // $1F, as used by rgbasm and Pan Docs
+	BASE_0X                        // 0x1F
+	BASE_H                         // 1Fh, with a leading 0 when the first digit is a letter
+)
+
+// OperandStyle selects the syntax used for memory operands
+type OperandStyle int
+
+const (
+	STYLE_PANDOCS OperandStyle = iota // ld [hl+], a and ldh [n8], a
+	STYLE_RGBDS                       // ld [hli], a and ldh [n16], a
+	STYLE_NOCASH                      // ldi (hl),a and ld (ff00+n8),a as shown by no$gmb and BGB
+)
+
+// Formatter renders instructions within the context of the whole program, so that
+// branch targets can be shown as absolute addresses or label names. The zero value
+// writes bare instructions in decimal, DefaultFormatter is what Cartridge.String uses.
+type Formatter struct {
+	Base  NumberBase
+	Style OperandStyle
+	//Write mnemonics, registers and conditions in upper case
+	Uppercase bool
+	//Keep the a operand of 8 bit arithmetic and logic, add a, b rather than add b
+	ExplicitA bool
+	//Pad mnemonics and columns so that operands and comments line up
+	Align bool
+	//Columns written by listings alongside each instruction
+	Address  bool
+	Bytes    bool
+	Cycles   bool
+	Comments bool
+
+	labels  map[Location]string
+	targets map[int]Location
+	//Names given to memory addresses, which replace them in operands
+	symbols map[Location]string
+	//Output only syntax that rgbasm accepts
+	rgbds bool
+}
+
+// DefaultFormatter writes each instruction after its address in the Pan Docs syntax
+func DefaultFormatter() Formatter {
+	return Formatter{ExplicitA: true, Address: true, Comments: true}
+}
+
+// rgbdsFormatter writes instructions which rgbasm assembles back to the same bytes
+func rgbdsFormatter() Formatter {
+	return Formatter{Base: BASE_DOLLAR, ExplicitA: true, rgbds: true}
+}
+
+// withContext returns a copy of the formatter which names locations in the program
+func (f Formatter) withContext(labels map[Location]string, targets map[int]Location, symbols map[Location]string) Formatter {
+	f.labels, f.targets, f.symbols = labels, targets, symbols
+	return f
+}
+
+// Instruction renders the mnemonic and operands of an instruction
+func (f Formatter) Instruction(ins Instruction) string {
+	//Invalid opcodes have no mnemonic in rgbasm, and stop is always followed by a 0
+	//so any other padding byte can only be kept as data
+	padded := ins.Opcode == OP_STOP && len(ins.Bytes) == 2 && ins.Bytes[1] != 0
+	if padded || (f.rgbds && ins.Opcode == OP_INVALID) {
+		return fmt.Sprintf("%s ; %s", f.data(ins.Bytes), ins.Opcode)
+	}
+
+	mnemonic := ins.Opcode.String()
+	operands := f.operands(ins)
+	switch {
+	case !f.ExplicitA && isALU(ins.Opcode) && len(ins.Operands) == 2 && ins.Operands[0] == (Operand{Kind: OPERAND_REG, Register: REG_A}):
+		operands = operands[1:]
+	case f.Style == STYLE_NOCASH && ins.Opcode == OP_LDH:
+		mnemonic = "ld"
+	case f.Style == STYLE_NOCASH && ins.Opcode == OP_LD:
+		for _, op := range ins.Operands {
+			if op.Register == REG_HLI && op.Kind == OPERAND_REG {
+				mnemonic = "ldi"
+			} else if op.Register == REG_HLD && op.Kind == OPERAND_REG {
+				mnemonic = "ldd"
+			}
+		}
+	}
+	return f.join(f.keyword(mnemonic), operands)
+}
+
+// isALU reports whether an opcode is 8 bit arithmetic or logic which always targets a
+func isALU(op Opcode) bool {
+	switch op {
+	case OP_ADD, OP_ADC, OP_SUB, OP_SBC, OP_AND, OP_XOR, OP_OR, OP_CP:
+		return true
+	}
+	return false
+}
+
+// line renders an instruction with the columns selected for listings
+func (f Formatter) line(ins Instruction) string {
+	str := f.Instruction(ins)
+	if comment := ins.Comment(); f.Comments && comment != "" {
+		if f.Align {
+			str = fmt.Sprintf("%-24s", str)
+		}
+		str += " ; " + comment
+	}
+	return strings.Join(append(f.columns(ins.Location(), ins.Bytes, cyclesColumn(ins)), str), " ")
+}
+
+// dataLine renders bytes which aren't an instruction as db with the same columns as line
+func (f Formatter) dataLine(loc Location, bytes []byte) string {
+	return strings.Join(append(f.columns(loc, bytes, ""), f.data(bytes)), " ")
+}
+
+// columns returns the address, bytes and cycles columns selected for listings, data
+// has no cycles so the column is only kept when it needs padding
+func (f Formatter) columns(loc Location, bytes []byte, cycles string) []string {
+	columns := []string{}
+	if f.Address {
+		columns = append(columns, loc.String())
+	}
+	if f.Bytes {
+		values := make([]string, len(bytes))
+		for i, b := range bytes {
+			values[i] = fmt.Sprintf("%02X", b)
+		}
+		str := strings.Join(values, " ")
+		if f.Align {
+			str = fmt.Sprintf("%-8s", str)
+		}
+		columns = append(columns, str)
+	}
+	if f.Cycles && (cycles != "" || f.Align) {
+		if f.Align {
+			cycles = fmt.Sprintf("%-11s", cycles)
+		}
+		columns = append(columns, cycles)
+	}
+	return columns
+}
+
+// operands renders each operand of an instruction, with branch targets replaced by their label
+func (f Formatter) operands(ins Instruction) []string {
+	operands := make([]string, len(ins.Operands))
+	for i, op := range ins.Operands {
+		operands[i] = f.operand(op)
+	}
+
+	if idx, ok := branchOperand(ins); ok {
+		if target, ok := f.targets[ins.Offset]; ok {
+			if name, ok := f.labels[target]; ok {
+				operands[idx] = name
+			} else if ins.Opcode == OP_JR {
+				operands[idx] = f.number(target.Address, 4)
+			}
+		} else if ins.Opcode == OP_JR {
+			operands[idx] = f.number((ins.Address+ins.Length+ins.Operands[idx].Value)&0xFFFF, 4)
+		}
+	}
+
+	for i, op := range ins.Operands {
+		address := op.Value
+		switch {
+		case op.Kind == OPERAND_IMM16 && op.Indirect:
+		case op.Kind == OPERAND_IMM8 && op.Indirect:
+			address += 0xFF00
+		default:
+			continue
+		}
+		if name, ok := f.symbol(address, ins.Bank); ok {
+			operands[i] = f.indirect(name)
+		}
+	}
+	return operands
+}
+
+// symbol finds the name of a memory address accessed by code running from bank
+func (f Formatter) symbol(address int, bank int) (string, bool) {
+	if len(f.symbols) == 0 {
+		return "", false
+	}
+	if address <= SWITCHABLE_END {
+		loc, ok := resolveAddress(address, bank)
+		if !ok {
+			return "", false
+		}
+		name, ok := f.symbols[loc]
+		return name, ok
+	}
+	//RAM banks aren't tracked so the name in the lowest bank is used
+	for b := 0; b <= 0xFF; b++ {
+		if name, ok := f.symbols[Location{Bank: b, Address: address}]; ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+func (f Formatter) operand(o Operand) string {
+	var str string
+	switch o.Kind {
+	case OPERAND_REG:
+		str = f.register(o.Register)
+		//[c] is short for the address 0xFF00 + c
+		if o.Register == REG_C && o.Indirect && f.Style == STYLE_NOCASH {
+			str = "ff00+" + str
+		}
+	case OPERAND_COND:
+		return f.keyword(o.Cond.String())
+	case OPERAND_SP_OFFSET:
+		sp := f.keyword(REG_SP.String())
+		if f.Style == STYLE_NOCASH {
+			return sp + f.signed(o.Value, true)
+		}
+		return sp + " " + f.signed(o.Value, true)
+	case OPERAND_SIMM8:
+		return f.signed(o.Value, false)
+	case OPERAND_IMM8:
+		str = f.number(o.Value, 2)
+		//ldh addresses are offsets into the 0xFF00 page, which rgbasm wants in full
+		if o.Indirect {
+			if name, ok := HardwareRegister(0xFF00 + o.Value); ok {
+				str = name
+			} else if f.rgbds || f.Style == STYLE_RGBDS {
+				str = f.number(0xFF00+o.Value, 4)
+			} else if f.Style == STYLE_NOCASH {
+				str = "ff00+" + str
+			}
+		}
+	case OPERAND_IMM16:
+		str = f.number(o.Value, 4)
+		if name, ok := HardwareRegister(o.Value); ok && o.Indirect {
+			str = name
+		}
+	case OPERAND_VECTOR:
+		str = f.number(o.Value, 2)
+	default:
+		str = fmt.Sprintf("%d", o.Value)
+	}
+	if o.Indirect {
+		return f.indirect(str)
+	}
+	return str
+}
+
+func (f Formatter) register(r Register) string {
+	name := r.String()
+	switch {
+	case f.Style == STYLE_RGBDS && r == REG_HLI:
+		name = "hli"
+	case f.Style == STYLE_RGBDS && r == REG_HLD:
+		name = "hld"
+	case f.Style == STYLE_NOCASH && (r == REG_HLI || r == REG_HLD):
+		//The increment is part of the ldi and ldd mnemonics
+		name = REG_HL.String()
+	}
+	return f.keyword(name)
+}
+
+// keyword applies the letter case to mnemonics, registers and conditions
+func (f Formatter) keyword(s string) string {
+	if f.Uppercase {
+		return strings.ToUpper(s)
+	}
+	return s
+}
+
+func (f Formatter) indirect(s string) string {
+	if f.Style == STYLE_NOCASH {
+		return "(" + s + ")"
+	}
+	return "[" + s + "]"
+}
+
+func (f Formatter) number(value int, digits int) string {
+	switch f.Base {
+	case BASE_DOLLAR:
+		return fmt.Sprintf("$%0*X", digits, value)
+	case BASE_0X:
+		return fmt.Sprintf("0x%0*X", digits, value)
+	case BASE_H:
+		str := fmt.Sprintf("%0*X", digits, value)
+		if str[0] > '9' {
+			str = "0" + str
+		}
+		return str + "h"
+	}
+	return fmt.Sprintf("%d", value)
+}
+
+// signed renders a signed offset, spaced as an operator when it follows a register
+func (f Formatter) signed(value int, operator bool) string {
+	sign := "-"
+	if value >= 0 {
+		sign = "+"
+	}
+	magnitude := f.number(max(value, -value), 2)
+	switch {
+	case operator && f.Style != STYLE_NOCASH:
+		return sign + " " + magnitude
+	case operator || value < 0:
+		return sign + magnitude
+	}
+	return magnitude
+}
+
+// data renders bytes which aren't part of an instruction as a db directive
+func (f Formatter) data(bytes []byte) string {
+	values := make([]string, len(bytes))
+	for i, b := range bytes {
+		values[i] = f.number(int(b), 2)
+	}
+	return f.keyword("db") + " " + strings.Join(values, f.separator())
+}
+
+// branchOperand returns the index of the operand holding the target of a jp, call or jr
+func branchOperand(ins Instruction) (int, bool) {
+	if len(ins.Operands) == 0 {
+		return 0, false
+	}
+	idx := len(ins.Operands) - 1
+	switch ins.Opcode {
+	case OP_JP, OP_CALL:
+		return idx, ins.Operands[idx].Kind == OPERAND_IMM16
+	case OP_JR:
+		return idx, true
+	}
+	return 0, false
+}
+
+// separator is what goes between operands, no$gmb doesn't space them
+func (f Formatter) separator() string {
+	if f.Style == STYLE_NOCASH {
+		return ","
+	}
+	return ", "
+}
+
+// join writes the mnemonic followed by its operands
+func (f Formatter) join(mnemonic string, operands []string) string {
+	if len(operands) == 0 {
+		return mnemonic
+	}
+	if f.Align {
+		mnemonic = fmt.Sprintf("%-4s", mnemonic)
+	}
+	return mnemonic + " " + strings.Join(operands, f.separator())
+}
@@ -0,0 +1,79 @@
+package cartridge
+
+import "testing"
+
+func Test_FormatterOptions(t *testing.T) {
+	table := []struct {
+		name   string
+		format Formatter
+		input  []byte
+		str    string
+	}{
+		{"decimal", Formatter{}, []byte{0x3E, 0x1F}, "ld a, 31"},
+		{"dollar", Formatter{Base: BASE_DOLLAR}, []byte{0x3E, 0x1F}, "ld a, $1F"},
+		{"0x", Formatter{Base: BASE_0X}, []byte{0x21, 0x00, 0xC0}, "ld hl, 0xC000"},
+		{"h suffix", Formatter{Base: BASE_H}, []byte{0x3E, 0x1F}, "ld a, 1Fh"},
+		{"h suffix letter", Formatter{Base: BASE_H}, []byte{0x21, 0x00, 0xC0}, "ld hl, 0C000h"},
+		{"upper case", Formatter{Uppercase: true, ExplicitA: true}, []byte{0x80}, "ADD A, B"},
+		{"implicit a", Formatter{}, []byte{0xB8}, "cp b"},
+		{"implicit a keeps hl", Formatter{}, []byte{0x09}, "add hl, bc"},
+		{"explicit a", Formatter{ExplicitA: true}, []byte{0xB8}, "cp a, b"},
+		{"pandocs hl+", Formatter{}, []byte{0x22}, "ld [hl+], a"},
+		{"rgbds hli", Formatter{Style: STYLE_RGBDS}, []byte{0x3A}, "ld a, [hld]"},
+		{"rgbds ldh", Formatter{Style: STYLE_RGBDS, Base: BASE_DOLLAR}, []byte{0xE0, 0x80}, "ldh [$FF80], a"},
+		{"nocash ldi", Formatter{Style: STYLE_NOCASH}, []byte{0x22}, "ldi (hl),a"},
+		{"nocash ldh", Formatter{Style: STYLE_NOCASH, Base: BASE_DOLLAR}, []byte{0xF0, 0x80}, "ld a,(ff00+$80)"},
+		{"nocash ldh register", Formatter{Style: STYLE_NOCASH}, []byte{0xF0, 0x44}, "ld a,(rLY)"},
+		{"nocash c", Formatter{Style: STYLE_NOCASH}, []byte{0xE2}, "ld (ff00+c),a"},
+		{"nocash sp", Formatter{Style: STYLE_NOCASH, Base: BASE_DOLLAR}, []byte{0xF8, 0xFE}, "ld hl,sp-$02"},
+		{"aligned", Formatter{Align: true, ExplicitA: true}, []byte{0x3E, 0x1F}, "ld   a, 31"},
+	}
+	for _, check := range table {
+		ins, err := Decode(check.input, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if str := check.format.Instruction(ins); str != check.str {
+			t.Errorf("Expected %s to format %X as %q but found %q", check.name, check.input, check.str, str)
+		}
+	}
+}
+
+func Test_FormatterColumns(t *testing.T) {
+	ins, err := Decode([]byte{0xEA, 0x00, 0xC0}, 0x150)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	table := []struct {
+		format Formatter
+		str    string
+	}{
+		{DefaultFormatter(), "00:0150 ld [49152], a ; WRAM0"},
+		{Formatter{ExplicitA: true}, "ld [49152], a"},
+		{Formatter{Bytes: true, Comments: true}, "EA 00 C0 ld [49152], a ; WRAM0"},
+		{Formatter{Address: true, Bytes: true, Align: true, Base: BASE_DOLLAR}, "00:0150 EA 00 C0 ld   [$C000], a"},
+		{Formatter{Align: true, Comments: true, Base: BASE_DOLLAR}, "ld   [$C000], a          ; WRAM0"},
+	}
+	for _, check := range table {
+		if str := check.format.line(ins); str != check.str {
+			t.Errorf("Expected %q but found %q", check.str, str)
+		}
+	}
+}
+
+func Test_WithFormatter(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0x3E, 0x1F, // ld a, $1F
+			0x18, 0xFC}, // jr 0x150
+	})
+	c, err := New(rom, WithFormatter(Formatter{Base: BASE_DOLLAR, Address: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "jp_0_0150:\n00:0150 ld a, $1F\n00:0152 jr jp_0_0150\n"
+	if listing := c.Listing(0x150, 0x154); listing != want {
+		t.Errorf("Expected listing\n%s\nbut found\n%s", want, listing)
+	}
+}
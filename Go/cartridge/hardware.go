@@ -0,0 +1,126 @@
+package cartridge
+
+// Hardware registers as named in hardware.inc https://github.com/gbdev/hardware.inc
+var hardwareRegisters = map[int]string{
+	0xFF00: "rJOYP",
+	0xFF01: "rSB",
+	0xFF02: "rSC",
+	0xFF04: "rDIV",
+	0xFF05: "rTIMA",
+	0xFF06: "rTMA",
+	0xFF07: "rTAC",
+	0xFF0F: "rIF",
+	0xFF10: "rNR10",
+	0xFF11: "rNR11",
+	0xFF12: "rNR12",
+	0xFF13: "rNR13",
+	0xFF14: "rNR14",
+	0xFF16: "rNR21",
+	0xFF17: "rNR22",
+	0xFF18: "rNR23",
+	0xFF19: "rNR24",
+	0xFF1A: "rNR30",
+	0xFF1B: "rNR31",
+	0xFF1C: "rNR32",
+	0xFF1D: "rNR33",
+	0xFF1E: "rNR34",
+	0xFF20: "rNR41",
+	0xFF21: "rNR42",
+	0xFF22: "rNR43",
+	0xFF23: "rNR44",
+	0xFF24: "rNR50",
+	0xFF25: "rNR51",
+	0xFF26: "rNR52",
+	0xFF40: "rLCDC",
+	0xFF41: "rSTAT",
+	0xFF42: "rSCY",
+	0xFF43: "rSCX",
+	0xFF44: "rLY",
+	0xFF45: "rLYC",
+	0xFF46: "rDMA",
+	0xFF47: "rBGP",
+	0xFF48: "rOBP0",
+	0xFF49: "rOBP1",
+	0xFF4A: "rWY",
+	0xFF4B: "rWX",
+	0xFF4D: "rKEY1",
+	0xFF4F: "rVBK",
+	0xFF50: "rBANK",
+	0xFF51: "rHDMA1",
+	0xFF52: "rHDMA2",
+	0xFF53: "rHDMA3",
+	0xFF54: "rHDMA4",
+	0xFF55: "rHDMA5",
+	0xFF56: "rRP",
+	0xFF68: "rBCPS",
+	0xFF69: "rBCPD",
+	0xFF6A: "rOCPS",
+	0xFF6B: "rOCPD",
+	0xFF6C: "rOPRI",
+	0xFF70: "rSVBK",
+	0xFF76: "rPCM12",
+	0xFF77: "rPCM34",
+	0xFFFF: "rIE",
+}
+
+// Region is a named range of the CPU address space, both ends inclusive
+type Region struct {
+	Name  string
+	Start int
+	End   int
+}
+
+// MemoryMap is the CPU address space as laid out in https://gbdev.io/pandocs/Memory_Map.html
+var MemoryMap = []Region{
+	{"ROM0", 0x0000, 0x3FFF},
+	{"ROMX", 0x4000, 0x7FFF},
+	{"VRAM", 0x8000, 0x9FFF},
+	{"SRAM", 0xA000, 0xBFFF},
+	{"WRAM0", 0xC000, 0xCFFF},
+	{"WRAMX", 0xD000, 0xDFFF},
+	{"ECHO", 0xE000, 0xFDFF},
+	{"OAM", 0xFE00, 0xFE9F},
+	{"UNUSABLE", 0xFEA0, 0xFEFF},
+	{"IO", 0xFF00, 0xFF7F},
+	{"HRAM", 0xFF80, 0xFFFE},
+	{"IE", 0xFFFF, 0xFFFF},
+}
+
+// HardwareRegister returns the name of the I/O register at address
+func HardwareRegister(address int) (string, bool) {
+	name, ok := hardwareRegisters[address]
+	return name, ok
+}
+
+// HardwareRegisterAddress returns the address of the I/O register called name
+func HardwareRegisterAddress(name string) (int, bool) {
+	for address, register := range hardwareRegisters {
+		if register == name {
+			return address, true
+		}
+	}
+	return 0, false
+}
+
+// MemoryRegion returns the region of the address space that address falls in
+func MemoryRegion(address int) (Region, bool) {
+	for _, r := range MemoryMap {
+		if address >= r.Start && address <= r.End {
+			return r, true
+		}
+	}
+	return Region{}, false
+}
+
+// annotate describes where an address points to outside of ROM, used to comment
+// immediates which are likely to be pointers. ROM addresses are left to labels.
+func annotate(address int) string {
+	if name, ok := HardwareRegister(address); ok {
+		return name
+	}
+	r, ok := MemoryRegion(address)
+	if !ok || r.Name == "ROM0" || r.Name == "ROMX" {
+		return ""
+	}
+	return r.Name
+}
@@ -0,0 +1,321 @@
+package cartridge
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// CGBSupport is the Game Boy Color compatibility declared at 0x0143
+type CGBSupport int
+
+const (
+	CGB_NONE CGBSupport = iota
+	CGB_COMPATIBLE
+	CGB_ONLY
+)
+
+func (c CGBSupport) String() string {
+	switch c {
+	case CGB_COMPATIBLE:
+		return "CGB compatible"
+	case CGB_ONLY:
+		return "CGB only"
+	}
+	return "none"
+}
+
+// Mapper is the memory bank controller, or other hardware, used by a cartridge
+type Mapper int
+
+const (
+	MAPPER_UNKNOWN Mapper = iota
+	MAPPER_ROM_ONLY
+	MAPPER_MBC1
+	MAPPER_MBC2
+	MAPPER_MBC3
+	MAPPER_MBC5
+	MAPPER_MBC6
+	MAPPER_MBC7
+	MAPPER_MMM01
+	MAPPER_POCKET_CAMERA
+	MAPPER_TAMA5
+	MAPPER_HUC1
+	MAPPER_HUC3
+)
+
+var mapperNames = [...]string{
+	MAPPER_UNKNOWN:       "Unknown",
+	MAPPER_ROM_ONLY:      "ROM",
+	MAPPER_MBC1:          "MBC1",
+	MAPPER_MBC2:          "MBC2",
+	MAPPER_MBC3:          "MBC3",
+	MAPPER_MBC5:          "MBC5",
+	MAPPER_MBC6:          "MBC6",
+	MAPPER_MBC7:          "MBC7",
+	MAPPER_MMM01:         "MMM01",
+	MAPPER_POCKET_CAMERA: "POCKET CAMERA",
+	MAPPER_TAMA5:         "BANDAI TAMA5",
+	MAPPER_HUC1:          "HuC1",
+	MAPPER_HUC3:          "HuC3",
+}
+
+func (m Mapper) String() string {
+	if m < 0 || int(m) >= len(mapperNames) {
+		return fmt.Sprintf("Mapper(%d)", int(m))
+	}
+	return mapperNames[m]
+}
+
+// CartridgeType is the byte at 0x0147 decoded into the mapper and extra hardware on the cartridge
+type CartridgeType struct {
+	Code    byte
+	Mapper  Mapper
+	RAM     bool
+	Battery bool
+	Timer   bool
+	Rumble  bool
+	Sensor  bool
+}
+
+// Name returns the name of the cartridge type as listed in Pan Docs
+func (t CartridgeType) Name() string {
+	value, ok := cartridgeTypeMap[t.Code]
+	if !ok {
+		return "Unknown cartridge type"
+	}
+	return value
+}
+
+var cartridgeTypes = map[byte]CartridgeType{
+	0x00: {Mapper: MAPPER_ROM_ONLY},
+	0x01: {Mapper: MAPPER_MBC1},
+	0x02: {Mapper: MAPPER_MBC1, RAM: true},
+	0x03: {Mapper: MAPPER_MBC1, RAM: true, Battery: true},
+	0x05: {Mapper: MAPPER_MBC2},
+	0x06: {Mapper: MAPPER_MBC2, Battery: true},
+	0x08: {Mapper: MAPPER_ROM_ONLY, RAM: true},
+	0x09: {Mapper: MAPPER_ROM_ONLY, RAM: true, Battery: true},
+	0x0B: {Mapper: MAPPER_MMM01},
+	0x0C: {Mapper: MAPPER_MMM01, RAM: true},
+	0x0D: {Mapper: MAPPER_MMM01, RAM: true, Battery: true},
+	0x0F: {Mapper: MAPPER_MBC3, Timer: true, Battery: true},
+	0x10: {Mapper: MAPPER_MBC3, Timer: true, RAM: true, Battery: true},
+	0x11: {Mapper: MAPPER_MBC3},
+	0x12: {Mapper: MAPPER_MBC3, RAM: true},
+	0x13: {Mapper: MAPPER_MBC3, RAM: true, Battery: true},
+	0x19: {Mapper: MAPPER_MBC5},
+	0x1A: {Mapper: MAPPER_MBC5, RAM: true},
+	0x1B: {Mapper: MAPPER_MBC5, RAM: true, Battery: true},
+	0x1C: {Mapper: MAPPER_MBC5, Rumble: true},
+	0x1D: {Mapper: MAPPER_MBC5, Rumble: true, RAM: true},
+	0x1E: {Mapper: MAPPER_MBC5, Rumble: true, RAM: true, Battery: true},
+	0x20: {Mapper: MAPPER_MBC6},
+	0x22: {Mapper: MAPPER_MBC7, Sensor: true, Rumble: true, RAM: true, Battery: true},
+	0xFC: {Mapper: MAPPER_POCKET_CAMERA},
+	0xFD: {Mapper: MAPPER_TAMA5},
+	0xFE: {Mapper: MAPPER_HUC3},
+	0xFF: {Mapper: MAPPER_HUC1, RAM: true, Battery: true},
+}
+
+// DecodeCartridgeType decodes the cartridge type byte, unknown types have MAPPER_UNKNOWN
+func DecodeCartridgeType(code byte) CartridgeType {
+	t := cartridgeTypes[code]
+	t.Code = code
+	return t
+}
+
+// ROM sizes in banks of 16 KiB, 0x52-0x54 are listed by Pan Docs but no cartridges use them
+var romBanks = map[byte]int{
+	0x00: 2,
+	0x01: 4,
+	0x02: 8,
+	0x03: 16,
+	0x04: 32,
+	0x05: 64,
+	0x06: 128,
+	0x07: 256,
+	0x08: 512,
+	0x52: 72,
+	0x53: 80,
+	0x54: 96,
+}
+
+// RAM sizes in banks of 8 KiB, 0x01 was never used so is treated as 2 KiB
+var ramSizes = map[byte]int{
+	0x00: 0,
+	0x01: 2 * 1024,
+	0x02: 8 * 1024,
+	0x03: 32 * 1024,
+	0x04: 128 * 1024,
+	0x05: 64 * 1024,
+}
+
+// External RAM is mapped in banks of 8 KiB at 0xA000-0xBFFF
+const RAM_BANK_SIZE = 0x2000
+
+// Header is every field of the cartridge header at 0x0100-0x014F, see
+// https://gbdev.io/pandocs/The_Cartridge_Header.html
+type Header struct {
+	EntryPoint [4]byte
+	Logo       [48]byte
+
+	// The title was shortened as later fields were added, to 15 characters
+	// by the CGB flag and to 11 by the manufacturer code. Trailing NULs are removed.
+	Title16 string
+	Title15 string
+	Title11 string
+
+	ManufacturerCode string
+	CGBFlag          byte
+	CGB              CGBSupport
+	NewLicenseeCode  string
+	SGBFlag          byte
+	SGB              bool
+	CartridgeType    CartridgeType
+
+	ROMSizeCode byte
+	ROMSize     int
+	ROMBanks    int
+	RAMSizeCode byte
+	RAMSize     int
+	RAMBanks    int
+
+	DestinationCode byte
+	OldLicenseeCode byte
+	MaskROMVersion  byte
+	HeaderChecksum  byte
+	GlobalChecksum  uint16
+}
+
+var ErrFileTooSmall = errors.New("file is too small to hold a cartridge header")
+
+func checkHeaderSize(bytes []byte) error {
+	if len(bytes) <= GLOBAL_CHECKSUM_END {
+		return fmt.Errorf("%w: %d bytes but the header ends at %#x", ErrFileTooSmall, len(bytes), GLOBAL_CHECKSUM_END)
+	}
+	return nil
+}
+
+// ParseHeader reads the header fields from the start of a ROM, without disassembling any of it
+func ParseHeader(bytes []byte) (*Header, error) {
+	if err := checkHeaderSize(bytes); err != nil {
+		return nil, err
+	}
+
+	h := &Header{
+		ManufacturerCode: string(bytes[MANUFACTUTURER_CODE_START : MANUFACTUTURER_CODE_END+1]),
+		CGBFlag:          bytes[CGB_FLAG],
+		NewLicenseeCode:  string(bytes[NEW_LICENSEE_CODE_START : NEW_LICENSEE_CODE_END+1]),
+		SGBFlag:          bytes[SGB_FLAG],
+		CartridgeType:    DecodeCartridgeType(bytes[CARTRIDGE_TYPE]),
+		ROMSizeCode:      bytes[ROM_SIZE],
+		RAMSizeCode:      bytes[RAM_SIZE],
+		DestinationCode:  bytes[DESTINATION_CODE],
+		OldLicenseeCode:  bytes[OLD_LICENSEE_CODE],
+		MaskROMVersion:   bytes[MASK_ROM_VERSION],
+		HeaderChecksum:   bytes[HEADER_CHECKSUM],
+		GlobalChecksum:   binary.BigEndian.Uint16(bytes[GLOBAL_CHECKSUM_START : GLOBAL_CHECKSUM_END+1]),
+	}
+	copy(h.EntryPoint[:], bytes[ENTRY_POINT_START:ENTRY_POINT_END+1])
+	copy(h.Logo[:], bytes[NINTENDO_LOGO_START:NINTENDO_LOGO_END+1])
+
+	title := bytes[TITLE_START : TITLE_END+1]
+	h.Title16 = strings.TrimRight(string(title), "\x00")
+	h.Title15 = strings.TrimRight(string(title[:15]), "\x00")
+	h.Title11 = strings.TrimRight(string(title[:11]), "\x00")
+
+	//Bit 7 marks CGB support, with bit 6 also set for CGB only. Other values
+	//are part of the title on older cartridges
+	switch h.CGBFlag {
+	case 0x80:
+		h.CGB = CGB_COMPATIBLE
+	case 0xC0:
+		h.CGB = CGB_ONLY
+	}
+
+	h.SGB = h.SGBFlag == 0x03
+
+	if banks, ok := romBanks[h.ROMSizeCode]; ok {
+		h.ROMBanks = banks
+		h.ROMSize = banks * BANK_SIZE
+	}
+
+	//MBC2 has 512 half bytes built in and declares no RAM in the header
+	if size, ok := ramSizes[h.RAMSizeCode]; ok {
+		h.RAMSize = size
+		h.RAMBanks = (size + RAM_BANK_SIZE - 1) / RAM_BANK_SIZE
+	}
+
+	return h, nil
+}
+
+// Japanese reports whether the cartridge was sold in Japan rather than overseas
+func (h *Header) Japanese() bool {
+	return h.DestinationCode == 0x00
+}
+
+// Destination returns where the cartridge was intended to be sold
+func (h *Header) Destination() string {
+	switch h.DestinationCode {
+	case 0x00:
+		return "Japan"
+	case 0x01:
+		return "Overseas"
+	}
+	return "Unknown"
+}
+
+// Licensee returns the publisher, which is in the new licensee code when the old code is 0x33
+func (h *Header) Licensee() string {
+	if h.OldLicenseeCode != 0x33 {
+		value, ok := oldLicenseeCodeMap[h.OldLicenseeCode]
+		if !ok {
+			return "UNKNOWN"
+		}
+		return value
+	}
+
+	value, ok := newLicenseeCodeMap[h.NewLicenseeCode]
+	if !ok {
+		return "UNKNOWN NEW"
+	}
+	return value
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func (h *Header) String() string {
+	var builder strings.Builder
+	field := func(name string, format string, args ...any) {
+		fmt.Fprintf(&builder, "%-18s "+format+"\n", append([]any{name + ":"}, args...)...)
+	}
+
+	t := h.CartridgeType
+	field("Entry Point", "% X", h.EntryPoint)
+	field("Logo", "% X", h.Logo)
+	field("Title", "%q (15: %q, 11: %q)", h.Title16, h.Title15, h.Title11)
+	field("Manufacturer Code", "%q", h.ManufacturerCode)
+	field("CGB Flag", "$%02X (%s)", h.CGBFlag, h.CGB)
+	field("New Licensee Code", "%q", h.NewLicenseeCode)
+	field("SGB Flag", "$%02X (%s)", h.SGBFlag, yesNo(h.SGB))
+	field("Cartridge Type", "$%02X (%s)", t.Code, t.Name())
+	field("Mapper", "%s", t.Mapper)
+	field("Features", "RAM %s, battery %s, timer %s, rumble %s, sensor %s",
+		yesNo(t.RAM), yesNo(t.Battery), yesNo(t.Timer), yesNo(t.Rumble), yesNo(t.Sensor))
+	field("ROM Size", "$%02X (%d KiB, %d banks)", h.ROMSizeCode, h.ROMSize/1024, h.ROMBanks)
+	field("RAM Size", "$%02X (%d KiB, %d banks)", h.RAMSizeCode, h.RAMSize/1024, h.RAMBanks)
+	field("Destination", "$%02X (%s)", h.DestinationCode, h.Destination())
+	field("Old Licensee Code", "$%02X", h.OldLicenseeCode)
+	field("Licensee", "%s", h.Licensee())
+	field("Mask ROM Version", "$%02X", h.MaskROMVersion)
+	field("Header Checksum", "$%02X", h.HeaderChecksum)
+	field("Global Checksum", "$%04X", h.GlobalChecksum)
+	return builder.String()
+}
@@ -0,0 +1,77 @@
+package cartridge
+
+import (
+	"os"
+	"testing"
+)
+
+func Test_ParseHeader(t *testing.T) {
+	rom, err := os.ReadFile("../example/example.gb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h, err := ParseHeader(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h.Title16 != "ADDAMS FAMILY" || h.Title15 != "ADDAMS FAMILY" || h.Title11 != "ADDAMS FAMI" {
+		t.Errorf("unexpected titles %q %q %q", h.Title16, h.Title15, h.Title11)
+	}
+	if h.EntryPoint != [4]byte{0x00, 0xC3, 0x50, 0x01} {
+		t.Errorf("unexpected entry point % X", h.EntryPoint)
+	}
+	if string(h.Logo[:]) != string(nintendoLogo) {
+		t.Errorf("logo was not read")
+	}
+	if h.CGB != CGB_NONE || h.SGB {
+		t.Errorf("expected a DMG only cartridge, got %s and SGB %v", h.CGB, h.SGB)
+	}
+	if h.CartridgeType.Mapper != MAPPER_MBC1 || h.CartridgeType.RAM || h.CartridgeType.Battery {
+		t.Errorf("unexpected cartridge type %+v", h.CartridgeType)
+	}
+	if h.ROMBanks != 8 || h.ROMSize != 128*1024 || h.ROMBanks*BANK_SIZE != len(rom) {
+		t.Errorf("unexpected rom size %d in %d banks", h.ROMSize, h.ROMBanks)
+	}
+	if h.RAMSize != 0 || h.RAMBanks != 0 {
+		t.Errorf("unexpected ram size %d in %d banks", h.RAMSize, h.RAMBanks)
+	}
+	if h.Destination() != "Overseas" || h.OldLicenseeCode != 0x67 || h.Licensee() != "Ocean Software" {
+		t.Errorf("unexpected destination %s and licensee %02X %s", h.Destination(), h.OldLicenseeCode, h.Licensee())
+	}
+	if h.HeaderChecksum != 0xF0 || h.GlobalChecksum != 0x0455 {
+		t.Errorf("unexpected checksums %02X %04X", h.HeaderChecksum, h.GlobalChecksum)
+	}
+}
+
+func Test_ParseHeaderFields(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		TITLE_START: []byte("POKEMON_SLVAAXE"),
+		CGB_FLAG:    {0x80, '0', '1', 0x03, 0x10, 0x06, 0x03, 0x00, 0x33, 0x01},
+	})
+	h, err := ParseHeader(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if h.Title15 != "POKEMON_SLVAAXE" || h.Title11 != "POKEMON_SLV" || h.ManufacturerCode != "AAXE" {
+		t.Errorf("unexpected titles %q %q and manufacturer %q", h.Title15, h.Title11, h.ManufacturerCode)
+	}
+	if h.CGB != CGB_COMPATIBLE || !h.SGB {
+		t.Errorf("expected CGB compatible with SGB support, got %s and %v", h.CGB, h.SGB)
+	}
+	want := CartridgeType{Code: 0x10, Mapper: MAPPER_MBC3, Timer: true, RAM: true, Battery: true}
+	if h.CartridgeType != want {
+		t.Errorf("expected %+v, got %+v", want, h.CartridgeType)
+	}
+	if h.ROMBanks != 128 || h.RAMSize != 32*1024 || h.RAMBanks != 4 {
+		t.Errorf("unexpected sizes %d rom banks, %d bytes ram in %d banks", h.ROMBanks, h.RAMSize, h.RAMBanks)
+	}
+	if !h.Japanese() || h.Licensee() != "Nintendo Research & Development" || h.MaskROMVersion != 0x01 {
+		t.Errorf("unexpected destination %s, licensee %s and version %d", h.Destination(), h.Licensee(), h.MaskROMVersion)
+	}
+
+	if _, err := ParseHeader(rom[:GLOBAL_CHECKSUM_END]); err == nil {
+		t.Errorf("expected an error for a truncated header")
+	}
+}
@@ -0,0 +1,87 @@
+package cartridge
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Bytes shown on each row of data in a hexdump
+const HEXDUMP_ROW = 16
+
+// Hexdump renders file offsets from start up to but not including end with the bank,
+// address and raw bytes of every line so that it can be mapped back onto the ROM.
+// Code is shown decoded, the header is broken out into its fields and anything else
+// is dumped 16 bytes to a row along with its printable characters.
+func (c *Cartridge) Hexdump(start, end int) string {
+	start, end = max(start, 0), min(end, len(c.rom))
+	f := c.formatter()
+	f.Address, f.Bytes, f.Align = true, true, true
+
+	var builder strings.Builder
+	for offset := start; offset < end; {
+		if offset >= NINTENDO_LOGO_START && offset <= GLOBAL_CHECKSUM_END {
+			offset = c.hexdumpHeaderField(&builder, offset, end)
+			continue
+		}
+
+		if i, ok := c.xrefs.byOffset[offset]; ok && offset+c.instructions[i].Length <= end {
+			ins := c.instructions[i]
+			if offset == ENTRY_POINT_START {
+				builder.WriteString("; Entry point\n")
+			}
+			if label, ok := c.labels[ins.Location()]; ok {
+				builder.WriteString(label)
+				builder.WriteString(":\n")
+			}
+			builder.WriteString(f.line(ins))
+			builder.WriteRune('\n')
+			offset += ins.Length
+			continue
+		}
+
+		//Rows stop short at the next instruction or the header
+		rowEnd := min((offset/HEXDUMP_ROW+1)*HEXDUMP_ROW, end)
+		for next := offset + 1; next < rowEnd; next++ {
+			if _, ok := c.xrefs.byOffset[next]; ok || next == NINTENDO_LOGO_START {
+				rowEnd = next
+				break
+			}
+		}
+		c.hexdumpRow(&builder, offset, rowEnd)
+		offset = rowEnd
+	}
+	return builder.String()
+}
+
+// hexdumpHeaderField writes the header field containing offset and returns the offset after it
+func (c *Cartridge) hexdumpHeaderField(builder *strings.Builder, offset, end int) int {
+	for _, field := range headerFields {
+		if offset < field.start || offset > field.end {
+			continue
+		}
+		fieldEnd := min(field.end+1, end)
+		fmt.Fprintf(builder, "; %s: %s\n", field.name, field.value(c))
+		for row := offset; row < fieldEnd; row += HEXDUMP_ROW {
+			c.hexdumpRow(builder, row, min(row+HEXDUMP_ROW, fieldEnd))
+		}
+		return fieldEnd
+	}
+	//Every header byte belongs to a field, this only guards against looping forever
+	c.hexdumpRow(builder, offset, offset+1)
+	return offset + 1
+}
+
+// hexdumpRow writes the bytes from start up to end as hex followed by their printable characters
+func (c *Cartridge) hexdumpRow(builder *strings.Builder, start, end int) {
+	hex := make([]string, 0, HEXDUMP_ROW)
+	ascii := make([]byte, 0, HEXDUMP_ROW)
+	for _, b := range c.rom[start:end] {
+		hex = append(hex, fmt.Sprintf("%02X", b))
+		if b >= 0x20 && b < 0x7F {
+			ascii = append(ascii, b)
+		} else {
+			ascii = append(ascii, '.')
+		}
+	}
+	fmt.Fprintf(builder, "%s %-*s |%s|\n", OffsetLocation(start), HEXDUMP_ROW*3-1, strings.Join(hex, " "), ascii)
+}
@@ -0,0 +1,45 @@
+package cartridge
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_Hexdump(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0x21, 0x60, 0x01, // ld hl, 0x160
+			0x18, 0xFE}, // jr 0x153
+		0x160: []byte("Hello, World!\x00\x01\x02\x03"),
+	})
+	c, err := New(rom, WithFormatter(Formatter{Base: BASE_DOLLAR}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := strings.Join([]string{
+		"jp_0_0150:",
+		"00:0150 21 60 01 ld   hl, $0160",
+		"jr_0_0153:",
+		"00:0153 18 FE    jr   jr_0_0153",
+		"00:0155 FF FF FF FF FF FF FF FF FF FF FF                |...........|",
+		"00:0160 48 65 6C 6C 6F 2C 20 57 6F 72 6C 64 21 00 01 02 |Hello, World!...|",
+		"00:0170 03                                              |.|",
+		"",
+	}, "\n")
+	if dump := c.Hexdump(0x150, 0x171); dump != want {
+		t.Errorf("Expected\n%s\nbut found\n%s", want, dump)
+	}
+
+	header := c.Hexdump(0x100, 0x150)
+	for _, line := range []string{
+		"; Entry point\nentry:\n00:0100 C3 50 01 jp   jp_0_0150\n00:0103 FF ",
+		"; Nintendo logo: valid\n00:0104 CE ED 66 66",
+		"; Cartridge type: ROM ONLY\n00:0147 00 ",
+		"; Header checksum: invalid, expected $",
+	} {
+		if !strings.Contains(header, line) {
+			t.Errorf("Expected the header to contain\n%s\nbut found\n%s", line, header)
+		}
+	}
+}
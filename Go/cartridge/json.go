@@ -0,0 +1,227 @@
+package cartridge
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// codeJSON is a raw header value alongside the name it decodes to
+type codeJSON struct {
+	Value int    `json:"value"`
+	Name  string `json:"name"`
+}
+
+type licenseeJSON struct {
+	Value string `json:"value"`
+	Name  string `json:"name"`
+}
+
+type cartridgeTypeJSON struct {
+	Value   int    `json:"value"`
+	Name    string `json:"name"`
+	Mapper  string `json:"mapper"`
+	RAM     bool   `json:"ram"`
+	Battery bool   `json:"battery"`
+	Timer   bool   `json:"timer"`
+	Rumble  bool   `json:"rumble"`
+	Sensor  bool   `json:"sensor"`
+}
+
+type sizeJSON struct {
+	Value int `json:"value"`
+	Bytes int `json:"bytes"`
+	Banks int `json:"banks"`
+}
+
+type headerJSON struct {
+	EntryPoint       string            `json:"entry_point"`
+	Logo             string            `json:"logo"`
+	Title            string            `json:"title"`
+	Title15          string            `json:"title_15"`
+	Title11          string            `json:"title_11"`
+	ManufacturerCode string            `json:"manufacturer_code"`
+	CGB              codeJSON          `json:"cgb"`
+	NewLicensee      licenseeJSON      `json:"new_licensee"`
+	SGB              codeJSON          `json:"sgb"`
+	CartridgeType    cartridgeTypeJSON `json:"cartridge_type"`
+	ROMSize          sizeJSON          `json:"rom_size"`
+	RAMSize          sizeJSON          `json:"ram_size"`
+	Destination      codeJSON          `json:"destination"`
+	OldLicensee      codeJSON          `json:"old_licensee"`
+	Licensee         string            `json:"licensee"`
+	MaskROMVersion   int               `json:"mask_rom_version"`
+	HeaderChecksum   int               `json:"header_checksum"`
+	GlobalChecksum   int               `json:"global_checksum"`
+}
+
+func (h *Header) MarshalJSON() ([]byte, error) {
+	t := h.CartridgeType
+	oldLicensee, ok := oldLicenseeCodeMap[h.OldLicenseeCode]
+	if !ok {
+		oldLicensee = "UNKNOWN"
+	}
+	sgb := "none"
+	if h.SGB {
+		sgb = "supported"
+	}
+
+	return json.Marshal(headerJSON{
+		EntryPoint:       fmt.Sprintf("%X", h.EntryPoint),
+		Logo:             fmt.Sprintf("%X", h.Logo),
+		Title:            h.Title16,
+		Title15:          h.Title15,
+		Title11:          h.Title11,
+		ManufacturerCode: h.ManufacturerCode,
+		CGB:              codeJSON{Value: int(h.CGBFlag), Name: h.CGB.String()},
+		NewLicensee:      licenseeJSON{Value: h.NewLicenseeCode, Name: newLicenseeCodeMap[h.NewLicenseeCode]},
+		SGB:              codeJSON{Value: int(h.SGBFlag), Name: sgb},
+		CartridgeType: cartridgeTypeJSON{
+			Value:   int(t.Code),
+			Name:    t.Name(),
+			Mapper:  t.Mapper.String(),
+			RAM:     t.RAM,
+			Battery: t.Battery,
+			Timer:   t.Timer,
+			Rumble:  t.Rumble,
+			Sensor:  t.Sensor,
+		},
+		ROMSize:        sizeJSON{Value: int(h.ROMSizeCode), Bytes: h.ROMSize, Banks: h.ROMBanks},
+		RAMSize:        sizeJSON{Value: int(h.RAMSizeCode), Bytes: h.RAMSize, Banks: h.RAMBanks},
+		Destination:    codeJSON{Value: int(h.DestinationCode), Name: h.Destination()},
+		OldLicensee:    codeJSON{Value: int(h.OldLicenseeCode), Name: oldLicensee},
+		Licensee:       h.Licensee(),
+		MaskROMVersion: int(h.MaskROMVersion),
+		HeaderChecksum: int(h.HeaderChecksum),
+		GlobalChecksum: int(h.GlobalChecksum),
+	})
+}
+
+type checksumJSON struct {
+	Value    int  `json:"value"`
+	Expected int  `json:"expected"`
+	Valid    bool `json:"valid"`
+}
+
+type validationJSON struct {
+	Logo           bool         `json:"logo"`
+	HeaderChecksum checksumJSON `json:"header_checksum"`
+	GlobalChecksum checksumJSON `json:"global_checksum"`
+}
+
+type locationJSON struct {
+	Location string `json:"location"`
+	Bank     int    `json:"bank"`
+	Address  int    `json:"address"`
+	Label    string `json:"label,omitempty"`
+}
+
+type instructionJSON struct {
+	Type         string         `json:"type,omitempty"`
+	Offset       int            `json:"offset"`
+	Location     string         `json:"location"`
+	Bank         int            `json:"bank"`
+	Address      int            `json:"address"`
+	Label        string         `json:"label,omitempty"`
+	Bytes        string         `json:"bytes"`
+	Mnemonic     string         `json:"mnemonic"`
+	Operands     []string       `json:"operands"`
+	Text         string         `json:"text"`
+	Comment      string         `json:"comment,omitempty"`
+	Cycles       int            `json:"cycles"`
+	BranchCycles int            `json:"branch_cycles,omitempty"`
+	Target       *locationJSON  `json:"target,omitempty"`
+	ReferencedBy []locationJSON `json:"referenced_by,omitempty"`
+}
+
+type cartridgeJSON struct {
+	Type         string            `json:"type,omitempty"`
+	Header       *Header           `json:"header"`
+	Validation   validationJSON    `json:"validation"`
+	Instructions []instructionJSON `json:"instructions,omitempty"`
+}
+
+func (c *Cartridge) validationJSON() validationJSON {
+	headerChecksum := computeHeaderChecksum(c.rom)
+	globalChecksum := computeGlobalChecksum(c.rom)
+	storedGlobal := binary.BigEndian.Uint16(c.rom[GLOBAL_CHECKSUM_START:])
+	return validationJSON{
+		Logo: validateNintendoLogo(c.rom),
+		HeaderChecksum: checksumJSON{
+			Value:    int(c.rom[HEADER_CHECKSUM]),
+			Expected: int(headerChecksum),
+			Valid:    c.rom[HEADER_CHECKSUM] == headerChecksum,
+		},
+		GlobalChecksum: checksumJSON{
+			Value:    int(storedGlobal),
+			Expected: int(globalChecksum),
+			Valid:    storedGlobal == globalChecksum,
+		},
+	}
+}
+
+func (c *Cartridge) locationJSON(loc Location) locationJSON {
+	return locationJSON{Location: loc.String(), Bank: loc.Bank, Address: loc.Address, Label: c.labels[loc]}
+}
+
+func (c *Cartridge) instructionJSON(f Formatter, ins Instruction) instructionJSON {
+	loc := ins.Location()
+	j := instructionJSON{
+		Offset:       ins.Offset,
+		Location:     loc.String(),
+		Bank:         ins.Bank,
+		Address:      ins.Address,
+		Label:        c.labels[loc],
+		Bytes:        fmt.Sprintf("%X", ins.Bytes),
+		Mnemonic:     ins.Opcode.String(),
+		Operands:     f.operands(ins),
+		Text:         f.Instruction(ins),
+		Comment:      ins.Comment(),
+		Cycles:       ins.Cycles,
+		BranchCycles: ins.BranchCycles,
+	}
+	if target, ok := c.targets[ins.Offset]; ok {
+		t := c.locationJSON(target)
+		j.Target = &t
+	}
+	for _, xref := range c.BranchesTo(loc) {
+		j.ReferencedBy = append(j.ReferencedBy, c.locationJSON(xref.Instruction.Location()))
+	}
+	return j
+}
+
+// MarshalJSON encodes the header, the result of validating it and every decoded instruction
+func (c *Cartridge) MarshalJSON() ([]byte, error) {
+	f := c.formatter()
+	instructions := make([]instructionJSON, len(c.instructions))
+	for i, ins := range c.instructions {
+		instructions[i] = c.instructionJSON(f, ins)
+	}
+	return json.Marshal(cartridgeJSON{
+		Header:       c.Header,
+		Validation:   c.validationJSON(),
+		Instructions: instructions,
+	})
+}
+
+// WriteJSONLines writes the same information as MarshalJSON one object per line, for tools
+// which read a line at a time. The first line has a type of "cartridge" and holds the
+// header, each line after it has a type of "instruction".
+func (c *Cartridge) WriteJSONLines(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	err := encoder.Encode(cartridgeJSON{Type: "cartridge", Header: c.Header, Validation: c.validationJSON()})
+	if err != nil {
+		return err
+	}
+
+	f := c.formatter()
+	for _, ins := range c.instructions {
+		j := c.instructionJSON(f, ins)
+		j.Type = "instruction"
+		if err := encoder.Encode(j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,141 @@
+package cartridge
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func Test_MarshalJSON(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		CARTRIDGE_TYPE: {0x03, 0x01, 0x02},
+		0x100:          {0xC3, 0x50, 0x01},       // jp 0x150
+		0x150:          {0xCD, 0x60, 0x01, 0x76}, // call 0x160, halt
+		0x160:          {0xC9},                   // ret
+	})
+	if err := FixChecksums(rom); err != nil {
+		t.Fatal(err)
+	}
+	c, err := New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		Header struct {
+			CartridgeType struct {
+				Value   int    `json:"value"`
+				Name    string `json:"name"`
+				Mapper  string `json:"mapper"`
+				Battery bool   `json:"battery"`
+			} `json:"cartridge_type"`
+			RAMSize struct {
+				Banks int `json:"banks"`
+			} `json:"ram_size"`
+		} `json:"header"`
+		Validation struct {
+			Logo           bool `json:"logo"`
+			HeaderChecksum struct {
+				Valid bool `json:"valid"`
+			} `json:"header_checksum"`
+		} `json:"validation"`
+		Instructions []instructionJSON `json:"instructions"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	cartType := decoded.Header.CartridgeType
+	if cartType.Value != 3 || cartType.Name != cartridgeTypeMap[3] || cartType.Mapper != "MBC1" || !cartType.Battery {
+		t.Errorf("Unexpected cartridge type %+v", cartType)
+	}
+	if decoded.Header.RAMSize.Banks != 1 || !decoded.Validation.Logo || !decoded.Validation.HeaderChecksum.Valid {
+		t.Errorf("Unexpected header %s", data)
+	}
+
+	var call, ret *instructionJSON
+	for i, ins := range decoded.Instructions {
+		switch ins.Offset {
+		case 0x150:
+			call = &decoded.Instructions[i]
+		case 0x160:
+			ret = &decoded.Instructions[i]
+		}
+	}
+	if call == nil || ret == nil {
+		t.Fatalf("Expected instructions at 0x150 and 0x160 in %s", data)
+	}
+	if call.Mnemonic != "call" || call.Bytes != "CD6001" || call.Target == nil || call.Target.Label != "sub_0160" {
+		t.Errorf("Unexpected call %+v", call)
+	}
+	if ret.Label != "sub_0160" || len(ret.ReferencedBy) != 1 || ret.ReferencedBy[0].Address != 0x150 {
+		t.Errorf("Unexpected ret %+v", ret)
+	}
+
+	var lines bytes.Buffer
+	if err := c.WriteJSONLines(&lines); err != nil {
+		t.Fatal(err)
+	}
+	scanner := bufio.NewScanner(&lines)
+	count := 0
+	for scanner.Scan() {
+		var line struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatal(err)
+		}
+		expected := "instruction"
+		if count == 0 {
+			expected = "cartridge"
+		}
+		if line.Type != expected {
+			t.Errorf("Expected line %d to be a %s but found %s", count, expected, line.Type)
+		}
+		count++
+	}
+	if count != len(c.Instructions())+1 {
+		t.Errorf("Expected %d lines but found %d", len(c.Instructions())+1, count)
+	}
+}
+
+// failingWriter accepts a number of writes and then fails
+type failingWriter struct {
+	writes int
+}
+
+var errWriteFailed = errors.New("write failed")
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.writes == 0 {
+		return 0, errWriteFailed
+	}
+	w.writes--
+	return len(p), nil
+}
+
+// A failing writer stops the output at the line it failed on
+func Test_WriteJSONLinesStopsOnWriteError(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0x00, 0x00, 0x76}, // nop, nop, halt
+	})
+	c, err := New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := &failingWriter{writes: 2}
+	if err := c.WriteJSONLines(w); !errors.Is(err, errWriteFailed) {
+		t.Errorf("Expected the third line to fail but found %v", err)
+	}
+	if w.writes != 0 {
+		t.Errorf("Expected one write per line but %d were left", w.writes)
+	}
+}
@@ -0,0 +1,71 @@
+package cartridge
+
+import "fmt"
+
+// Fixed names for the locations the CPU starts executing from without a jump
+var vectorLabels = map[int]string{
+	0x40:              "int_vblank",
+	0x48:              "int_stat",
+	0x50:              "int_timer",
+	0x58:              "int_serial",
+	0x60:              "int_joypad",
+	ENTRY_POINT_START: "entry",
+}
+
+// Higher ranked labels win when a location is the target of several kinds of branch
+const (
+	rankJr = iota
+	rankJp
+	rankCall
+	rankVector
+)
+
+// generateLabels names every decoded location which is the target of a branch
+// along with the entry points, named by the kind of branch used to reach them
+func generateLabels(p program) map[Location]string {
+	decoded := map[Location]bool{}
+	for _, ins := range p.instructions {
+		decoded[ins.Location()] = true
+	}
+
+	labels := map[Location]string{}
+	ranks := map[Location]int{}
+	add := func(loc Location, name string, rank int) {
+		if !decoded[loc] {
+			return
+		}
+		if existing, ok := ranks[loc]; ok && existing >= rank {
+			return
+		}
+		labels[loc] = name
+		ranks[loc] = rank
+	}
+
+	for address, name := range vectorLabels {
+		add(Location{Bank: 0, Address: address}, name, rankVector)
+	}
+	for _, address := range rstVectors {
+		add(Location{Bank: 0, Address: address}, fmt.Sprintf("rst_%02X", address), rankVector)
+	}
+
+	for _, ins := range p.instructions {
+		target, ok := p.targets[ins.Offset]
+		if !ok {
+			continue
+		}
+		switch ins.Opcode {
+		case OP_CALL:
+			if target.Bank == 0 {
+				add(target, fmt.Sprintf("sub_%04X", target.Address), rankCall)
+			} else {
+				add(target, fmt.Sprintf("sub_%X_%04X", target.Bank, target.Address), rankCall)
+			}
+		case OP_JP:
+			add(target, fmt.Sprintf("jp_%X_%04X", target.Bank, target.Address), rankJp)
+		case OP_JR:
+			add(target, fmt.Sprintf("jr_%X_%04X", target.Bank, target.Address), rankJr)
+		}
+	}
+
+	return labels
+}
@@ -2,442 +2,357 @@ package cartridge
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 )
 
-var r8Map = [8]string{"b", "c", "d", "e", "h", "l", "[hl]", "a"}
-var r16Map = [4]string{"bc", "de", "hl", "sp"}
-var r16StkMap = [4]string{"bc", "de", "hl", "af"}
-var r16MemMap = [4]string{"bc", "de", "hl+", "hl-"}
-var condMap = [4]string{"nz", "z", "nc", "c"}
+//go:generate go run ../tools -spec opcodes.txt -out opcodes_table.go
+
+// Opcode identifies the operation of a decoded instruction independent of its operands
+type Opcode int
+
+const (
+	OP_INVALID Opcode = iota
+	OP_NOP
+	OP_LD
+	OP_LDH
+	OP_INC
+	OP_DEC
+	OP_ADD
+	OP_ADC
+	OP_SUB
+	OP_SBC
+	OP_AND
+	OP_XOR
+	OP_OR
+	OP_CP
+	OP_RLCA
+	OP_RRCA
+	OP_RLA
+	OP_RRA
+	OP_DAA
+	OP_CPL
+	OP_SCF
+	OP_CCF
+	OP_JR
+	OP_JP
+	OP_CALL
+	OP_RET
+	OP_RETI
+	OP_RST
+	OP_STOP
+	OP_HALT
+	OP_DI
+	OP_EI
+	OP_POP
+	OP_PUSH
+	OP_RLC
+	OP_RRC
+	OP_RL
+	OP_RR
+	OP_SLA
+	OP_SRA
+	OP_SWAP
+	OP_SRL
+	OP_BIT
+	OP_RES
+	OP_SET
+)
 
-// Values are broken up in line with https://gbdev.io/pandocs/CPU_Instruction_Set.html
-// as of 19/2/2025
-func dissassembleNextBytes(bytes []byte) (string, int) {
+var opcodeNames = [...]string{
+	OP_INVALID: "CPU Hard Locked",
+	OP_NOP:     "nop",
+	OP_LD:      "ld",
+	OP_LDH:     "ldh",
+	OP_INC:     "inc",
+	OP_DEC:     "dec",
+	OP_ADD:     "add",
+	OP_ADC:     "adc",
+	OP_SUB:     "sub",
+	OP_SBC:     "sbc",
+	OP_AND:     "and",
+	OP_XOR:     "xor",
+	OP_OR:      "or",
+	OP_CP:      "cp",
+	OP_RLCA:    "rlca",
+	OP_RRCA:    "rrca",
+	OP_RLA:     "rla",
+	OP_RRA:     "rra",
+	OP_DAA:     "daa",
+	OP_CPL:     "cpl",
+	OP_SCF:     "scf",
+	OP_CCF:     "ccf",
+	OP_JR:      "jr",
+	OP_JP:      "jp",
+	OP_CALL:    "call",
+	OP_RET:     "ret",
+	OP_RETI:    "reti",
+	OP_RST:     "rst",
+	OP_STOP:    "stop",
+	OP_HALT:    "halt",
+	OP_DI:      "di",
+	OP_EI:      "ei",
+	OP_POP:     "pop",
+	OP_PUSH:    "push",
+	OP_RLC:     "rlc",
+	OP_RRC:     "rrc",
+	OP_RL:      "rl",
+	OP_RR:      "rr",
+	OP_SLA:     "sla",
+	OP_SRA:     "sra",
+	OP_SWAP:    "swap",
+	OP_SRL:     "srl",
+	OP_BIT:     "bit",
+	OP_RES:     "res",
+	OP_SET:     "set",
+}
+
+func (o Opcode) String() string {
+	if o < 0 || int(o) >= len(opcodeNames) {
+		return fmt.Sprintf("Opcode(%d)", int(o))
+	}
+	return opcodeNames[o]
+}
+
+// Register names an 8 or 16 bit register, including the auto incrementing hl forms
+type Register int
+
+const (
+	REG_B Register = iota
+	REG_C
+	REG_D
+	REG_E
+	REG_H
+	REG_L
+	REG_A
+	REG_BC
+	REG_DE
+	REG_HL
+	REG_SP
+	REG_AF
+	REG_HLI
+	REG_HLD
+)
+
+var registerNames = [...]string{
+	REG_B:   "b",
+	REG_C:   "c",
+	REG_D:   "d",
+	REG_E:   "e",
+	REG_H:   "h",
+	REG_L:   "l",
+	REG_A:   "a",
+	REG_BC:  "bc",
+	REG_DE:  "de",
+	REG_HL:  "hl",
+	REG_SP:  "sp",
+	REG_AF:  "af",
+	REG_HLI: "hl+",
+	REG_HLD: "hl-",
+}
+
+func (r Register) String() string {
+	if r < 0 || int(r) >= len(registerNames) {
+		return fmt.Sprintf("Register(%d)", int(r))
+	}
+	return registerNames[r]
+}
+
+// Condition is the flag test used by conditional jumps, calls and returns
+type Condition int
+
+const (
+	COND_NZ Condition = iota
+	COND_Z
+	COND_NC
+	COND_C
+)
 
-	instruction := bytes[0]
-
-	switch instruction {
-	//nop
-	case 0x00:
-		return "nop", 1
-
-	//ld r16 imm16
-	case 0x01, 0x11, 0x21, 0x31:
-		param := (instruction & 0b00110000) >> 4
-		paramName := r16Map[param]
-		value := binary.LittleEndian.Uint16(bytes[1:3])
-		return fmt.Sprintf("ld %s, %d", paramName, value), 3
-
-	//ld [r16mem], a
-	case 0x02, 0x12, 0x22, 0x32:
-		param := (instruction & 0b00110000) >> 4
-		paramName := r16MemMap[param]
-		return fmt.Sprintf("ld [%s], a", paramName), 1
-
-	//ld a, [r16mem]
-	case 0x0A, 0x1A, 0x2A, 0x3A:
-		param := (instruction & 0b00110000) >> 4
-		paramName := r16MemMap[param]
-		return fmt.Sprintf("ld a, [%s]", paramName), 1
-
-	//ld [imm16], sp
-	case 0x08:
-		value := binary.LittleEndian.Uint16(bytes[1:3])
-		return fmt.Sprintf("ld [%d], sp", value), 3
-
-	//inc r16
-	case 0x03, 0x13, 0x23, 0x33:
-		param := (instruction & 0b00110000) >> 4
-		paramName := r16Map[param]
-		return fmt.Sprintf("inc %s", paramName), 1
-
-	//dec r16
-	case 0x0B, 0x1B, 0x2B, 0x3B:
-		param := (instruction & 0b00110000) >> 4
-		paramName := r16Map[param]
-		return fmt.Sprintf("dec %s", paramName), 1
-
-	//add hl, r16
-	case 0x09, 0x19, 0x29, 0x39:
-		param := (instruction & 0b00111000) >> 4
-		paramName := r16Map[param]
-		return fmt.Sprintf("add hl, %s", paramName), 1
-
-	//inc r8
-	case 0x04, 0x14, 0x24, 0x34, 0x0C, 0x1C, 0x2C, 0x3C:
-		operand := (instruction & 0b00111000) >> 3
-		paramName := r8Map[operand]
-		return fmt.Sprintf("inc %s", paramName), 1
-
-	//dec r8
-	case 0x05, 0x15, 0x25, 0x35, 0x0D, 0x1D, 0x2D, 0x3D:
-		operand := (instruction & 0b00111000) >> 3
-		paramName := r8Map[operand]
-		return fmt.Sprintf("dec %s", paramName), 1
-
-	//ld r8, imm8
-	case 0x06, 0x0E, 0x16, 0x1E, 0x26, 0x2E, 0x36, 0x3E:
-		operand := (instruction & 0b00111000) >> 3
-		paramName := r8Map[operand]
-		val := bytes[1]
-		return fmt.Sprintf("ld %s, %d", paramName, val), 2
-
-	//rlca
-	case 0x07:
-		return "rlca", 1
-
-	//rrca
-	case 0x0F:
-		return "rrca", 1
-
-	//rla
-	case 0x17:
-		return "rla", 1
-
-	//rra
-	case 0x1F:
-		return "rra", 1
-
-	//daa
-	case 0x27:
-		return "daa", 1
-
-	//cpl
-	case 0x2F:
-		return "cpl", 1
-
-	//scf
-	case 0x37:
-		return "scf", 1
-
-	//ccf
-	case 0x3F:
-		return "ccf", 1
-
-	//jr imm8
-	case 0x18:
-		val := bytes[1]
-		return fmt.Sprintf("jr %d", val), 2
-
-	//jr cond, imm8
-	case 0x20, 0x30, 0x28, 0x38:
-		cond := (instruction & 0b00011000) >> 3
-		condName := condMap[cond]
-		val := bytes[1]
-		return fmt.Sprintf("jr %s, %d", condName, val), 2
-
-	//stop
-	case 0x10:
-		return "stop", 1
-
-	//ld r8, r8
-	case 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46,
-		0x47, 0x48, 0x49, 0x4A, 0x4B, 0x4C, 0x4D, 0x4E, 0x4F, 0x50,
-		0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5A,
-		0x5B, 0x5C, 0x5D, 0x5E, 0x5F, 0x60, 0x61, 0x62, 0x63, 0x64,
-		0x65, 0x66, 0x67, 0x68, 0x69, 0x6A, 0x6B, 0x6C, 0x6D, 0x6E,
-		0x6F, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x77, 0x78,
-		0x79, 0x7A, 0x7B, 0x7C, 0x7D, 0x7E, 0x7F:
-		src := (instruction & 0b00111000) >> 3
-		dest := (instruction & 0b00000111)
-		srcName := r8Map[src]
-		destName := r8Map[dest]
-		return fmt.Sprintf("ld %s, %s", srcName, destName), 1
-
-	//halt
-	case 0x76:
-		return "halt", 1
-
-	// add a, r8
-	case 0x80, 0x81, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87:
-		operand := (instruction & 0b00000111)
-		operandName := r8Map[operand]
-		return fmt.Sprintf("add a, %s", operandName), 1
-
-	// adc a, r8
-	case 0x88, 0x89, 0x8A, 0x8B, 0x8C, 0x8D, 0x8E, 0x8F:
-		operand := (instruction & 0b00000111)
-		operandName := r8Map[operand]
-		return fmt.Sprintf("adc a, %s", operandName), 1
-
-	// sub a, r8
-	case 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97:
-		operand := (instruction & 0b00000111)
-		operandName := r8Map[operand]
-		return fmt.Sprintf("sub a, %s", operandName), 1
-
-	// sbc a, r8
-	case 0x98, 0x99, 0x9A, 0x9B, 0x9C, 0x9D, 0x9E, 0x9F:
-		operand := (instruction & 0b00000111)
-		operandName := r8Map[operand]
-		return fmt.Sprintf("sbc a, %s", operandName), 1
-
-	// and a, r8
-	case 0xA0, 0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7:
-		operand := (instruction & 0b00000111)
-		operandName := r8Map[operand]
-		return fmt.Sprintf("and a, %s", operandName), 1
-
-	// xor a, r8
-	case 0xA8, 0xA9, 0xAA, 0xAB, 0xAC, 0xAD, 0xAE, 0xAF:
-		operand := (instruction & 0b00000111)
-		operandName := r8Map[operand]
-		return fmt.Sprintf("xor a, %s", operandName), 1
-
-	// or a, r8
-	case 0xB0, 0xB1, 0xB2, 0xB3, 0xB4, 0xB5, 0xB6, 0xB7:
-		operand := (instruction & 0b00000111)
-		operandName := r8Map[operand]
-		return fmt.Sprintf("or a, %s", operandName), 1
-
-	// cp a, r8
-	case 0xB8, 0xB9, 0xBA, 0xBB, 0xBC, 0xBD, 0xBE, 0xBF:
-		operand := (instruction & 0b00000111)
-		operandName := r8Map[operand]
-		return fmt.Sprintf("cp a, %s", operandName), 1
-
-	// add a, imm8
-	case 0xC6:
-		value := bytes[1]
-		return fmt.Sprintf("add a, %d", value), 2
-
-	// adc a, imm8
-	case 0xCE:
-		value := bytes[1]
-		return fmt.Sprintf("adc a, %d", value), 2
-
-	//sub a, imm8
-	case 0xD6:
-		value := bytes[1]
-		return fmt.Sprintf("sub a, %d", value), 2
-
-	//sbc a, imm8
-	case 0xDE:
-		value := bytes[1]
-		return fmt.Sprintf("sbc a, %d", value), 2
-
-	//and a, imm8
-	case 0xE6:
-		value := bytes[1]
-		return fmt.Sprintf("and a, %d", value), 2
-
-	// xor a, imm8
-	case 0xEE:
-		value := bytes[1]
-		return fmt.Sprintf("xor a, %d", value), 2
-
-	//or a, imm8
-	case 0xF6:
-		value := bytes[1]
-		return fmt.Sprintf("or a, %d", value), 2
-
-	// cp a, imm8
-	case 0xFE:
-		value := bytes[1]
-		return fmt.Sprintf("cp a, %d", value), 2
-
-	// ret cond
-	case 0xC0, 0xC8, 0xD0, 0xD8:
-		cond := (instruction & 0b00011000) >> 3
-		condName := condMap[cond]
-		return fmt.Sprintf("ret %s", condName), 1
-
-	// ret
-	case 0xC9:
-		return "ret", 1
-
-	// reti
-	case 0xD9:
-		return "reti", 1
-
-	// jp cond imm16
-	case 0xC2, 0xCA, 0xD2, 0xDA:
-		cond := (instruction & 0b00011000) >> 3
-		condName := condMap[cond]
-		value := binary.LittleEndian.Uint16(bytes[1:])
-		return fmt.Sprintf("jp %s, %d", condName, value), 3
-
-	// jp imm16
-	case 0xC3:
-		value := binary.LittleEndian.Uint16(bytes[1:])
-		return fmt.Sprintf("jp %d", value), 3
-
-	// jp hl
-	case 0xE9:
-		return "jp hl", 1
-
-	// call cond imm16
-	case 0xC4, 0xCC, 0xD4, 0xDC:
-		cond := (instruction & 0b00011000) >> 3
-		condName := condMap[cond]
-		value := binary.LittleEndian.Uint16(bytes[1:])
-		return fmt.Sprintf("call %s, %d", condName, value), 3
-
-	// call imm16
-	case 0xCD:
-		value := binary.LittleEndian.Uint16(bytes[1:])
-		return fmt.Sprintf("call %d", value), 3
-
-	// rst tgt3
-	case 0xC7, 0xCF, 0xD7, 0xDF, 0xE7, 0xEF, 0xF7, 0xFF:
-		tgt := (instruction & 0b00111000) >> 3
-		return fmt.Sprintf("rst %d", tgt), 1
-
-	//pop r16stk
-	case 0xC1, 0xD1, 0xE1, 0xF1:
-		reg := (instruction & 0b00110000) >> 4
-		regName := r16StkMap[reg]
-		return fmt.Sprintf("pop %s", regName), 1
-
-	//push r16stk
-	case 0xC5, 0xD5, 0xE5, 0xF5:
-		reg := (instruction & 0b00110000) >> 4
-		regName := r16StkMap[reg]
-		return fmt.Sprintf("push %s", regName), 1
-
-	//Prefix
-	case 0xCB:
-		{
-			operand := bytes[1] & 0b00000111
-			switch bytes[1] {
-			//rlc r8
-			case 0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07:
-				regName := r8Map[operand]
-				return fmt.Sprintf("rlc %s", regName), 2
-
-			//rrc r8
-			case 0x08, 0x09, 0x0A, 0x0B, 0x0C, 0x0D, 0x0E, 0x0F:
-				regName := r8Map[operand]
-				return fmt.Sprintf("rrc %s", regName), 2
-
-			//rl r8
-			case 0x10, 0x11, 0x12, 0x13, 0x14, 0x15, 0x16, 0x17:
-				regName := r8Map[operand]
-				return fmt.Sprintf("rl %s", regName), 2
-
-			//rr r8
-			case 0x18, 0x19, 0x1A, 0x1B, 0x1C, 0x1D, 0x1E, 0x1F:
-				regName := r8Map[operand]
-				return fmt.Sprintf("rr %s", regName), 2
-
-			//sla r8
-			case 0x20, 0x21, 0x22, 0x23, 0x24, 0x25, 0x26, 0x27:
-				regName := r8Map[operand]
-				return fmt.Sprintf("sla %s", regName), 2
-
-			//sra r8
-			case 0x28, 0x29, 0x2A, 0x2B, 0x2C, 0x2D, 0x2E, 0x2F:
-				regName := r8Map[operand]
-				return fmt.Sprintf("sra %s", regName), 2
-
-			//swap r8
-			case 0x30, 0x31, 0x32, 0x33, 0x34, 0x35, 0x36, 0x37:
-				regName := r8Map[operand]
-				return fmt.Sprintf("swap %s", regName), 2
-
-			//srl r8
-			case 0x38, 0x39, 0x3A, 0x3B, 0x3C, 0x3D, 0x3E, 0x3F:
-				regName := r8Map[operand]
-				return fmt.Sprintf("srl %s", regName), 2
-
-			//bit b3, r8
-			case 0x40, 0x41, 0x42, 0x43, 0x44, 0x45, 0x46,
-				0x47, 0x48, 0x49, 0x4A, 0x4B, 0x4C, 0x4D, 0x4E, 0x4F, 0x50,
-				0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59, 0x5A,
-				0x5B, 0x5C, 0x5D, 0x5E, 0x5F, 0x60, 0x61, 0x62, 0x63, 0x64,
-				0x65, 0x66, 0x67, 0x68, 0x69, 0x6A, 0x6B, 0x6C, 0x6D, 0x6E,
-				0x6F, 0x70, 0x71, 0x72, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78,
-				0x79, 0x7A, 0x7B, 0x7C, 0x7D, 0x7E, 0x7F:
-				bitIndex := (bytes[1] & 0b00111000) >> 3
-				operandName := r8Map[operand]
-				return fmt.Sprintf("bit %d, %s", bitIndex, operandName), 2
-
-			//res b3, r8
-			case 0x80, 0x81, 0x82,
-				0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89, 0x8A, 0x8B, 0x8C,
-				0x8D, 0x8E, 0x8F, 0x90, 0x91, 0x92, 0x93, 0x94, 0x95, 0x96,
-				0x97, 0x98, 0x99, 0x9A, 0x9B, 0x9C, 0x9D, 0x9E, 0x9F, 0xA0,
-				0xA1, 0xA2, 0xA3, 0xA4, 0xA5, 0xA6, 0xA7, 0xA8, 0xA9, 0xAA,
-				0xAB, 0xAC, 0xAD, 0xAE, 0xAF, 0xB0, 0xB1, 0xB2, 0xB3, 0xB4,
-				0xB5, 0xB6, 0xB7, 0xB8, 0xB9, 0xBA, 0xBB, 0xBC, 0xBD, 0xBE, 0xBF:
-				bitIndex := (bytes[1] & 0b00111000) >> 3
-				operandName := r8Map[operand]
-				return fmt.Sprintf("res %d, %s", bitIndex, operandName), 2
-
-			//set b3, r8
-			case 0xC0, 0xC1, 0xC2, 0xC3, 0xC4, 0xC5, 0xC6, 0xC7, 0xC8,
-				0xC9, 0xCA, 0xCB, 0xCC, 0xCD, 0xCE, 0xCF, 0xD0, 0xD1, 0xD2,
-				0xD3, 0xD4, 0xD5, 0xD6, 0xD7, 0xD8, 0xD9, 0xDA, 0xDB, 0xDC,
-				0xDD, 0xDE, 0xDF, 0xE0, 0xE1, 0xE2, 0xE3, 0xE4, 0xE5, 0xE6,
-				0xE7, 0xE8, 0xE9, 0xEA, 0xEB, 0xEC, 0xED, 0xEE, 0xEF, 0xF0,
-				0xF1, 0xF2, 0xF3, 0xF4, 0xF5, 0xF6, 0xF7, 0xF8, 0xF9, 0xFA,
-				0xFB, 0xFC, 0xFD, 0xFE, 0xFF:
-				bitIndex := (bytes[1] & 0b00111000) >> 3
-				operandName := r8Map[operand]
-				return fmt.Sprintf("set %d, %s", bitIndex, operandName), 2
-
-			default:
-				return fmt.Sprintf("Unknown 0xCB Prefixed instruction %02x", bytes[1]), 2
+var conditionNames = [...]string{"nz", "z", "nc", "c"}
+
+func (c Condition) String() string {
+	if c < 0 || int(c) >= len(conditionNames) {
+		return fmt.Sprintf("Condition(%d)", int(c))
+	}
+	return conditionNames[c]
+}
+
+type OperandKind int
+
+const (
+	OPERAND_REG       OperandKind = iota // Register, [Register] when Indirect
+	OPERAND_COND                         // Cond
+	OPERAND_IMM8                         // Value, [Value] when Indirect
+	OPERAND_IMM16                        // Value, [Value] when Indirect
+	OPERAND_OFFSET                       // Value is a signed relative offset
+	OPERAND_SIMM8                        // Value is a signed 8 bit immediate
+	OPERAND_SP_OFFSET                    // sp + Value, where Value is signed
+	OPERAND_BIT                          // Value is a bit index 0-7
+	OPERAND_VECTOR                       // Value is the rst target address
+)
+
+type Operand struct {
+	Kind     OperandKind
+	Register Register
+	Cond     Condition
+	Value    int
+	Indirect bool
+}
+
+func (o Operand) String() string {
+	return DefaultFormatter().operand(o)
+}
+
+// Instruction is a single decoded instruction. Cycles are T-cycles, for conditional
+// instructions Cycles is the cost when the branch is not taken and BranchCycles when it is.
+type Instruction struct {
+	Offset       int
+	Bank         int
+	Address      int
+	Bytes        []byte
+	Opcode       Opcode
+	Operands     []Operand
+	Length       int
+	Cycles       int
+	BranchCycles int
+}
+
+// Comment describes the memory referenced by the instruction when it points outside of ROM,
+// returning an empty string when there is nothing to add
+func (i Instruction) Comment() string {
+	switch i.Opcode {
+	case OP_JP, OP_CALL:
+		return ""
+	}
+	for _, op := range i.Operands {
+		switch {
+		case op.Kind == OPERAND_IMM16:
+			if _, ok := HardwareRegister(op.Value); ok && op.Indirect {
+				return ""
+			}
+			return annotate(op.Value)
+		case op.Kind == OPERAND_IMM8 && op.Indirect:
+			if _, ok := HardwareRegister(0xFF00 + op.Value); ok {
+				return ""
 			}
+			return annotate(0xFF00 + op.Value)
 		}
+	}
+	return ""
+}
 
-	//ldh [c], a
-	case 0xE2:
-		return "ldh [c], a", 1
-
-	//ldh [imm8], a
-	case 0xE0:
-		val := bytes[1]
-		return fmt.Sprintf("ldh %d, a", val), 2
-
-	//ld [imm16], a
-	case 0xEA:
-		val := binary.LittleEndian.Uint16(bytes[1:])
-		return fmt.Sprintf("ld %d, a", val), 3
-
-	//ldh a, [c]
-	case 0xF2:
-		return "ldh a, [c]", 1
-
-	//ldh a, [imm8]
-	case 0xF0:
-		val := bytes[1]
-		return fmt.Sprintf("ldh a, %d", val), 2
-
-	//ldh a, [imm16]
-	case 0xFA:
-		val := binary.LittleEndian.Uint16(bytes[1:])
-		return fmt.Sprintf("ldh a, %d", val), 3
-
-	//add sp, imm8
-	case 0xE8:
-		val := bytes[1]
-		return fmt.Sprintf("add sp, %d", val), 2
-
-	//ld hl, sp + imm8
-	case 0xF8:
-		val := bytes[1]
-		return fmt.Sprintf("ld hl, sp + %d", val), 2
-
-	//ld sp hl
-	case 0xF9:
-		return "ld sp, hl", 1
-
-	//di
-	case 0xF3:
-		return "di", 1
-
-	//ei
-	case 0xFB:
-		return "ei", 1
-
-	//invalid opcodes
-	case 0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC, 0xFD:
-		return "CPU Hard Locked", 1
+// Location returns the bank and CPU address the instruction is mapped to
+func (i Instruction) Location() Location {
+	return Location{Bank: i.Bank, Address: i.Address}
+}
+
+// String renders the instruction on its own, jr targets are shown as the absolute
+// address worked out from where the instruction was decoded
+func (i Instruction) String() string {
+	return DefaultFormatter().Instruction(i)
+}
+
+// operandEncoding is where the value of an operand comes from in the instruction bytes
+type operandEncoding int
+
+const (
+	ENCODING_NONE operandEncoding = iota // Fixed by the opcode
+	ENCODING_U8                          // The byte after the opcode
+	ENCODING_S8                          // The byte after the opcode, sign extended
+	ENCODING_U16                         // The two bytes after the opcode, little endian
+)
+
+type operandSpec struct {
+	operand  Operand
+	encoding operandEncoding
+}
+
+// opcodeSpec is an entry of the generated decoding tables in opcodes_table.go
+type opcodeSpec struct {
+	opcode       Opcode
+	operands     []operandSpec
+	length       int
+	cycles       int
+	branchCycles int
+}
+
+// dissassembleNextBytes renders the instruction at the start of bytes, or the
+// remaining bytes as data when they end part way through an instruction
+func dissassembleNextBytes(bytes []byte) (string, int) {
+	if len(bytes) == 0 {
+		return "", 0
 	}
+	ins, err := Decode(bytes, 0)
+	if err != nil {
+		return DefaultFormatter().data(bytes), len(bytes)
+	}
+	return ins.String(), ins.Length
+}
 
-	// panic(fmt.Sprintf("Unknown operand %8x", instruction))
-	//Temp just to keep things moving and parse whole file
-	return fmt.Sprintf("ERROR unknown : %2x: %8b", instruction, instruction), 1
+var ErrTruncatedInstruction = errors.New("truncated instruction")
+
+// TruncatedInstructionError is returned by Decode when the bytes end before the operands of an instruction
+type TruncatedInstructionError struct {
+	Offset    int
+	Needed    int
+	Available int
+}
+
+func (e *TruncatedInstructionError) Error() string {
+	return fmt.Sprintf("truncated instruction at %#x: needs %d bytes but only %d remain", e.Offset, e.Needed, e.Available)
+}
+
+func (e *TruncatedInstructionError) Unwrap() error {
+	return ErrTruncatedInstruction
+}
+
+// Decode decodes the instruction at the start of bytes, offset is the file offset of bytes[0]
+// and is used to work out the bank and address of the instruction.
+// Instructions are looked up in the tables generated from opcodes.txt, which follows
+// https://gbdev.io/pandocs/CPU_Instruction_Set.html
+func Decode(bytes []byte, offset int) (Instruction, error) {
+	//Decode from a copy padded to the longest instruction so missing operands read as
+	//zero, the length is then known and checked against what is really there
+	padded := bytes
+	if len(bytes) < 3 {
+		padded = make([]byte, 3)
+		copy(padded, bytes)
+	}
+	ins := decodeInstruction(padded)
+	if len(bytes) == 0 || ins.Length > len(bytes) {
+		return Instruction{}, &TruncatedInstructionError{Offset: offset, Needed: max(ins.Length, 1), Available: len(bytes)}
+	}
+
+	ins.Offset = offset
+	loc := OffsetLocation(offset)
+	ins.Bank, ins.Address = loc.Bank, loc.Address
+	ins.Bytes = append([]byte(nil), bytes[:ins.Length]...)
+	return ins, nil
+}
+
+// decodeInstruction decodes from bytes which are at least as long as the longest instruction
+func decodeInstruction(bytes []byte) Instruction {
+	spec := &baseOpcodes[bytes[0]]
+	if bytes[0] == 0xCB {
+		spec = &prefixedOpcodes[bytes[1]]
+	}
+
+	ins := Instruction{Opcode: spec.opcode, Length: spec.length, Cycles: spec.cycles, BranchCycles: spec.branchCycles}
+	if len(spec.operands) == 0 {
+		return ins
+	}
+	ins.Operands = make([]Operand, len(spec.operands))
+	for i, s := range spec.operands {
+		op := s.operand
+		switch s.encoding {
+		case ENCODING_U8:
+			op.Value = int(bytes[1])
+		case ENCODING_S8:
+			op.Value = int(int8(bytes[1]))
+		case ENCODING_U16:
+			op.Value = int(binary.LittleEndian.Uint16(bytes[1:3]))
+		}
+		ins.Operands[i] = op
+	}
+	return ins
 }
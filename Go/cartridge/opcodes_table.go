@@ -0,0 +1,1035 @@
+// Code generated by go run ../tools from opcodes.txt; DO NOT EDIT.
+
+package cartridge
+
+// baseOpcodes decodes the first byte of an instruction
+var baseOpcodes = [256]opcodeSpec{
+	// nop
+	0x00: {opcode: OP_NOP, length: 1, cycles: 4},
+	// ld bc, n16
+	0x01: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_BC}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12},
+	// ld [bc], a
+	0x02: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_BC, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc bc
+	0x03: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_BC}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc b
+	0x04: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// dec b
+	0x05: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld b, n8
+	0x06: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rlca
+	0x07: {opcode: OP_RLCA, length: 1, cycles: 4},
+	// ld [a16], sp
+	0x08: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_IMM16, Indirect: true}, ENCODING_U16}, {Operand{Kind: OPERAND_REG, Register: REG_SP}, ENCODING_NONE}}, length: 3, cycles: 20},
+	// add hl, bc
+	0x09: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_BC}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld a, [bc]
+	0x0A: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_BC, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// dec bc
+	0x0B: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_BC}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc c
+	0x0C: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// dec c
+	0x0D: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld c, n8
+	0x0E: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rrca
+	0x0F: {opcode: OP_RRCA, length: 1, cycles: 4},
+	// stop
+	0x10: {opcode: OP_STOP, length: 2, cycles: 4},
+	// ld de, n16
+	0x11: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_DE}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12},
+	// ld [de], a
+	0x12: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_DE, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc de
+	0x13: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_DE}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc d
+	0x14: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// dec d
+	0x15: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld d, n8
+	0x16: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rla
+	0x17: {opcode: OP_RLA, length: 1, cycles: 4},
+	// jr e8
+	0x18: {opcode: OP_JR, operands: []operandSpec{{Operand{Kind: OPERAND_OFFSET}, ENCODING_S8}}, length: 2, cycles: 12},
+	// add hl, de
+	0x19: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_DE}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld a, [de]
+	0x1A: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_DE, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// dec de
+	0x1B: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_DE}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc e
+	0x1C: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// dec e
+	0x1D: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld e, n8
+	0x1E: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rra
+	0x1F: {opcode: OP_RRA, length: 1, cycles: 4},
+	// jr nz, e8
+	0x20: {opcode: OP_JR, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_NZ}, ENCODING_NONE}, {Operand{Kind: OPERAND_OFFSET}, ENCODING_S8}}, length: 2, cycles: 8, branchCycles: 12},
+	// ld hl, n16
+	0x21: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12},
+	// ld [hl+], a
+	0x22: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HLI, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc hl
+	0x23: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc h
+	0x24: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// dec h
+	0x25: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld h, n8
+	0x26: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// daa
+	0x27: {opcode: OP_DAA, length: 1, cycles: 4},
+	// jr z, e8
+	0x28: {opcode: OP_JR, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_Z}, ENCODING_NONE}, {Operand{Kind: OPERAND_OFFSET}, ENCODING_S8}}, length: 2, cycles: 8, branchCycles: 12},
+	// add hl, hl
+	0x29: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld a, [hl+]
+	0x2A: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HLI, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// dec hl
+	0x2B: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc l
+	0x2C: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// dec l
+	0x2D: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld l, n8
+	0x2E: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// cpl
+	0x2F: {opcode: OP_CPL, length: 1, cycles: 4},
+	// jr nc, e8
+	0x30: {opcode: OP_JR, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_NC}, ENCODING_NONE}, {Operand{Kind: OPERAND_OFFSET}, ENCODING_S8}}, length: 2, cycles: 8, branchCycles: 12},
+	// ld sp, n16
+	0x31: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_SP}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12},
+	// ld [hl-], a
+	0x32: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HLD, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc sp
+	0x33: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_SP}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc [hl]
+	0x34: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 12},
+	// dec [hl]
+	0x35: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 12},
+	// ld [hl], n8
+	0x36: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 12},
+	// scf
+	0x37: {opcode: OP_SCF, length: 1, cycles: 4},
+	// jr c, e8
+	0x38: {opcode: OP_JR, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_OFFSET}, ENCODING_S8}}, length: 2, cycles: 8, branchCycles: 12},
+	// add hl, sp
+	0x39: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_SP}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld a, [hl-]
+	0x3A: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HLD, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// dec sp
+	0x3B: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_SP}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// inc a
+	0x3C: {opcode: OP_INC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// dec a
+	0x3D: {opcode: OP_DEC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld a, n8
+	0x3E: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// ccf
+	0x3F: {opcode: OP_CCF, length: 1, cycles: 4},
+	// ld b, b
+	0x40: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld b, c
+	0x41: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld b, d
+	0x42: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld b, e
+	0x43: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld b, h
+	0x44: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld b, l
+	0x45: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld b, [hl]
+	0x46: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld b, a
+	0x47: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld c, b
+	0x48: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld c, c
+	0x49: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld c, d
+	0x4A: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld c, e
+	0x4B: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld c, h
+	0x4C: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld c, l
+	0x4D: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld c, [hl]
+	0x4E: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld c, a
+	0x4F: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld d, b
+	0x50: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld d, c
+	0x51: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld d, d
+	0x52: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld d, e
+	0x53: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld d, h
+	0x54: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld d, l
+	0x55: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld d, [hl]
+	0x56: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld d, a
+	0x57: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld e, b
+	0x58: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld e, c
+	0x59: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld e, d
+	0x5A: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld e, e
+	0x5B: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld e, h
+	0x5C: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld e, l
+	0x5D: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld e, [hl]
+	0x5E: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld e, a
+	0x5F: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld h, b
+	0x60: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld h, c
+	0x61: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld h, d
+	0x62: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld h, e
+	0x63: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld h, h
+	0x64: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld h, l
+	0x65: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld h, [hl]
+	0x66: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld h, a
+	0x67: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld l, b
+	0x68: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld l, c
+	0x69: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld l, d
+	0x6A: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld l, e
+	0x6B: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld l, h
+	0x6C: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld l, l
+	0x6D: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld l, [hl]
+	0x6E: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld l, a
+	0x6F: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld [hl], b
+	0x70: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld [hl], c
+	0x71: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld [hl], d
+	0x72: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld [hl], e
+	0x73: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld [hl], h
+	0x74: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld [hl], l
+	0x75: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// halt
+	0x76: {opcode: OP_HALT, length: 1, cycles: 4},
+	// ld [hl], a
+	0x77: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld a, b
+	0x78: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld a, c
+	0x79: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld a, d
+	0x7A: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld a, e
+	0x7B: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld a, h
+	0x7C: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld a, l
+	0x7D: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld a, [hl]
+	0x7E: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld a, a
+	0x7F: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// add a, b
+	0x80: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// add a, c
+	0x81: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// add a, d
+	0x82: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// add a, e
+	0x83: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// add a, h
+	0x84: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// add a, l
+	0x85: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// add a, [hl]
+	0x86: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// add a, a
+	0x87: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// adc a, b
+	0x88: {opcode: OP_ADC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// adc a, c
+	0x89: {opcode: OP_ADC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// adc a, d
+	0x8A: {opcode: OP_ADC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// adc a, e
+	0x8B: {opcode: OP_ADC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// adc a, h
+	0x8C: {opcode: OP_ADC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// adc a, l
+	0x8D: {opcode: OP_ADC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// adc a, [hl]
+	0x8E: {opcode: OP_ADC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// adc a, a
+	0x8F: {opcode: OP_ADC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sub a, b
+	0x90: {opcode: OP_SUB, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sub a, c
+	0x91: {opcode: OP_SUB, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sub a, d
+	0x92: {opcode: OP_SUB, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sub a, e
+	0x93: {opcode: OP_SUB, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sub a, h
+	0x94: {opcode: OP_SUB, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sub a, l
+	0x95: {opcode: OP_SUB, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sub a, [hl]
+	0x96: {opcode: OP_SUB, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// sub a, a
+	0x97: {opcode: OP_SUB, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sbc a, b
+	0x98: {opcode: OP_SBC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sbc a, c
+	0x99: {opcode: OP_SBC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sbc a, d
+	0x9A: {opcode: OP_SBC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sbc a, e
+	0x9B: {opcode: OP_SBC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sbc a, h
+	0x9C: {opcode: OP_SBC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sbc a, l
+	0x9D: {opcode: OP_SBC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// sbc a, [hl]
+	0x9E: {opcode: OP_SBC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// sbc a, a
+	0x9F: {opcode: OP_SBC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// and a, b
+	0xA0: {opcode: OP_AND, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// and a, c
+	0xA1: {opcode: OP_AND, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// and a, d
+	0xA2: {opcode: OP_AND, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// and a, e
+	0xA3: {opcode: OP_AND, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// and a, h
+	0xA4: {opcode: OP_AND, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// and a, l
+	0xA5: {opcode: OP_AND, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// and a, [hl]
+	0xA6: {opcode: OP_AND, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// and a, a
+	0xA7: {opcode: OP_AND, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// xor a, b
+	0xA8: {opcode: OP_XOR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// xor a, c
+	0xA9: {opcode: OP_XOR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// xor a, d
+	0xAA: {opcode: OP_XOR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// xor a, e
+	0xAB: {opcode: OP_XOR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// xor a, h
+	0xAC: {opcode: OP_XOR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// xor a, l
+	0xAD: {opcode: OP_XOR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// xor a, [hl]
+	0xAE: {opcode: OP_XOR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// xor a, a
+	0xAF: {opcode: OP_XOR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// or a, b
+	0xB0: {opcode: OP_OR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// or a, c
+	0xB1: {opcode: OP_OR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// or a, d
+	0xB2: {opcode: OP_OR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// or a, e
+	0xB3: {opcode: OP_OR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// or a, h
+	0xB4: {opcode: OP_OR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// or a, l
+	0xB5: {opcode: OP_OR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// or a, [hl]
+	0xB6: {opcode: OP_OR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// or a, a
+	0xB7: {opcode: OP_OR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// cp a, b
+	0xB8: {opcode: OP_CP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// cp a, c
+	0xB9: {opcode: OP_CP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// cp a, d
+	0xBA: {opcode: OP_CP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// cp a, e
+	0xBB: {opcode: OP_CP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// cp a, h
+	0xBC: {opcode: OP_CP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// cp a, l
+	0xBD: {opcode: OP_CP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// cp a, [hl]
+	0xBE: {opcode: OP_CP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// cp a, a
+	0xBF: {opcode: OP_CP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ret nz
+	0xC0: {opcode: OP_RET, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_NZ}, ENCODING_NONE}}, length: 1, cycles: 8, branchCycles: 20},
+	// pop bc
+	0xC1: {opcode: OP_POP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_BC}, ENCODING_NONE}}, length: 1, cycles: 12},
+	// jp nz, a16
+	0xC2: {opcode: OP_JP, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_NZ}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12, branchCycles: 16},
+	// jp a16
+	0xC3: {opcode: OP_JP, operands: []operandSpec{{Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 16},
+	// call nz, a16
+	0xC4: {opcode: OP_CALL, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_NZ}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12, branchCycles: 24},
+	// push bc
+	0xC5: {opcode: OP_PUSH, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_BC}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// add a, n8
+	0xC6: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rst $00
+	0xC7: {opcode: OP_RST, operands: []operandSpec{{Operand{Kind: OPERAND_VECTOR, Value: 0x00}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// ret z
+	0xC8: {opcode: OP_RET, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_Z}, ENCODING_NONE}}, length: 1, cycles: 8, branchCycles: 20},
+	// ret
+	0xC9: {opcode: OP_RET, length: 1, cycles: 16},
+	// jp z, a16
+	0xCA: {opcode: OP_JP, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_Z}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12, branchCycles: 16},
+	// prefix
+	0xCB: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// call z, a16
+	0xCC: {opcode: OP_CALL, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_Z}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12, branchCycles: 24},
+	// call a16
+	0xCD: {opcode: OP_CALL, operands: []operandSpec{{Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 24},
+	// adc a, n8
+	0xCE: {opcode: OP_ADC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rst $08
+	0xCF: {opcode: OP_RST, operands: []operandSpec{{Operand{Kind: OPERAND_VECTOR, Value: 0x08}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// ret nc
+	0xD0: {opcode: OP_RET, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_NC}, ENCODING_NONE}}, length: 1, cycles: 8, branchCycles: 20},
+	// pop de
+	0xD1: {opcode: OP_POP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_DE}, ENCODING_NONE}}, length: 1, cycles: 12},
+	// jp nc, a16
+	0xD2: {opcode: OP_JP, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_NC}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12, branchCycles: 16},
+	// -
+	0xD3: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// call nc, a16
+	0xD4: {opcode: OP_CALL, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_NC}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12, branchCycles: 24},
+	// push de
+	0xD5: {opcode: OP_PUSH, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_DE}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// sub a, n8
+	0xD6: {opcode: OP_SUB, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rst $10
+	0xD7: {opcode: OP_RST, operands: []operandSpec{{Operand{Kind: OPERAND_VECTOR, Value: 0x10}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// ret c
+	0xD8: {opcode: OP_RET, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_C}, ENCODING_NONE}}, length: 1, cycles: 8, branchCycles: 20},
+	// reti
+	0xD9: {opcode: OP_RETI, length: 1, cycles: 16},
+	// jp c, a16
+	0xDA: {opcode: OP_JP, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12, branchCycles: 16},
+	// -
+	0xDB: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// call c, a16
+	0xDC: {opcode: OP_CALL, operands: []operandSpec{{Operand{Kind: OPERAND_COND, Cond: COND_C}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16}, ENCODING_U16}}, length: 3, cycles: 12, branchCycles: 24},
+	// -
+	0xDD: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// sbc a, n8
+	0xDE: {opcode: OP_SBC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rst $18
+	0xDF: {opcode: OP_RST, operands: []operandSpec{{Operand{Kind: OPERAND_VECTOR, Value: 0x18}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// ldh [a8], a
+	0xE0: {opcode: OP_LDH, operands: []operandSpec{{Operand{Kind: OPERAND_IMM8, Indirect: true}, ENCODING_U8}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 12},
+	// pop hl
+	0xE1: {opcode: OP_POP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}}, length: 1, cycles: 12},
+	// ldh [c], a
+	0xE2: {opcode: OP_LDH, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C, Indirect: true}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// -
+	0xE3: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// -
+	0xE4: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// push hl
+	0xE5: {opcode: OP_PUSH, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// and a, n8
+	0xE6: {opcode: OP_AND, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rst $20
+	0xE7: {opcode: OP_RST, operands: []operandSpec{{Operand{Kind: OPERAND_VECTOR, Value: 0x20}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// add sp, e8
+	0xE8: {opcode: OP_ADD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_SP}, ENCODING_NONE}, {Operand{Kind: OPERAND_SIMM8}, ENCODING_S8}}, length: 2, cycles: 16},
+	// jp hl
+	0xE9: {opcode: OP_JP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}}, length: 1, cycles: 4},
+	// ld [a16], a
+	0xEA: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_IMM16, Indirect: true}, ENCODING_U16}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 3, cycles: 16},
+	// -
+	0xEB: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// -
+	0xEC: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// -
+	0xED: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// xor a, n8
+	0xEE: {opcode: OP_XOR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rst $28
+	0xEF: {opcode: OP_RST, operands: []operandSpec{{Operand{Kind: OPERAND_VECTOR, Value: 0x28}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// ldh a, [a8]
+	0xF0: {opcode: OP_LDH, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8, Indirect: true}, ENCODING_U8}}, length: 2, cycles: 12},
+	// pop af
+	0xF1: {opcode: OP_POP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_AF}, ENCODING_NONE}}, length: 1, cycles: 12},
+	// ldh a, [c]
+	0xF2: {opcode: OP_LDH, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C, Indirect: true}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// di
+	0xF3: {opcode: OP_DI, length: 1, cycles: 4},
+	// -
+	0xF4: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// push af
+	0xF5: {opcode: OP_PUSH, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_AF}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// or a, n8
+	0xF6: {opcode: OP_OR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rst $30
+	0xF7: {opcode: OP_RST, operands: []operandSpec{{Operand{Kind: OPERAND_VECTOR, Value: 0x30}, ENCODING_NONE}}, length: 1, cycles: 16},
+	// ld hl, sp + e8
+	0xF8: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}, {Operand{Kind: OPERAND_SP_OFFSET}, ENCODING_S8}}, length: 2, cycles: 12},
+	// ld sp, hl
+	0xF9: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_SP}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL}, ENCODING_NONE}}, length: 1, cycles: 8},
+	// ld a, [a16]
+	0xFA: {opcode: OP_LD, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM16, Indirect: true}, ENCODING_U16}}, length: 3, cycles: 16},
+	// ei
+	0xFB: {opcode: OP_EI, length: 1, cycles: 4},
+	// -
+	0xFC: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// -
+	0xFD: {opcode: OP_INVALID, length: 1, cycles: 4},
+	// cp a, n8
+	0xFE: {opcode: OP_CP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}, {Operand{Kind: OPERAND_IMM8}, ENCODING_U8}}, length: 2, cycles: 8},
+	// rst $38
+	0xFF: {opcode: OP_RST, operands: []operandSpec{{Operand{Kind: OPERAND_VECTOR, Value: 0x38}, ENCODING_NONE}}, length: 1, cycles: 16},
+}
+
+// prefixedOpcodes decodes the byte following 0xCB
+var prefixedOpcodes = [256]opcodeSpec{
+	// rlc b
+	0x00: {opcode: OP_RLC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rlc c
+	0x01: {opcode: OP_RLC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rlc d
+	0x02: {opcode: OP_RLC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rlc e
+	0x03: {opcode: OP_RLC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rlc h
+	0x04: {opcode: OP_RLC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rlc l
+	0x05: {opcode: OP_RLC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rlc [hl]
+	0x06: {opcode: OP_RLC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// rlc a
+	0x07: {opcode: OP_RLC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rrc b
+	0x08: {opcode: OP_RRC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rrc c
+	0x09: {opcode: OP_RRC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rrc d
+	0x0A: {opcode: OP_RRC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rrc e
+	0x0B: {opcode: OP_RRC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rrc h
+	0x0C: {opcode: OP_RRC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rrc l
+	0x0D: {opcode: OP_RRC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rrc [hl]
+	0x0E: {opcode: OP_RRC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// rrc a
+	0x0F: {opcode: OP_RRC, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rl b
+	0x10: {opcode: OP_RL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rl c
+	0x11: {opcode: OP_RL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rl d
+	0x12: {opcode: OP_RL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rl e
+	0x13: {opcode: OP_RL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rl h
+	0x14: {opcode: OP_RL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rl l
+	0x15: {opcode: OP_RL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rl [hl]
+	0x16: {opcode: OP_RL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// rl a
+	0x17: {opcode: OP_RL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rr b
+	0x18: {opcode: OP_RR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rr c
+	0x19: {opcode: OP_RR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rr d
+	0x1A: {opcode: OP_RR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rr e
+	0x1B: {opcode: OP_RR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rr h
+	0x1C: {opcode: OP_RR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rr l
+	0x1D: {opcode: OP_RR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// rr [hl]
+	0x1E: {opcode: OP_RR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// rr a
+	0x1F: {opcode: OP_RR, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sla b
+	0x20: {opcode: OP_SLA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sla c
+	0x21: {opcode: OP_SLA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sla d
+	0x22: {opcode: OP_SLA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sla e
+	0x23: {opcode: OP_SLA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sla h
+	0x24: {opcode: OP_SLA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sla l
+	0x25: {opcode: OP_SLA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sla [hl]
+	0x26: {opcode: OP_SLA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// sla a
+	0x27: {opcode: OP_SLA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sra b
+	0x28: {opcode: OP_SRA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sra c
+	0x29: {opcode: OP_SRA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sra d
+	0x2A: {opcode: OP_SRA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sra e
+	0x2B: {opcode: OP_SRA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sra h
+	0x2C: {opcode: OP_SRA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sra l
+	0x2D: {opcode: OP_SRA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// sra [hl]
+	0x2E: {opcode: OP_SRA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// sra a
+	0x2F: {opcode: OP_SRA, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// swap b
+	0x30: {opcode: OP_SWAP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// swap c
+	0x31: {opcode: OP_SWAP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// swap d
+	0x32: {opcode: OP_SWAP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// swap e
+	0x33: {opcode: OP_SWAP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// swap h
+	0x34: {opcode: OP_SWAP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// swap l
+	0x35: {opcode: OP_SWAP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// swap [hl]
+	0x36: {opcode: OP_SWAP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// swap a
+	0x37: {opcode: OP_SWAP, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// srl b
+	0x38: {opcode: OP_SRL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// srl c
+	0x39: {opcode: OP_SRL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// srl d
+	0x3A: {opcode: OP_SRL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// srl e
+	0x3B: {opcode: OP_SRL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// srl h
+	0x3C: {opcode: OP_SRL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// srl l
+	0x3D: {opcode: OP_SRL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// srl [hl]
+	0x3E: {opcode: OP_SRL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// srl a
+	0x3F: {opcode: OP_SRL, operands: []operandSpec{{Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 0, b
+	0x40: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 0, c
+	0x41: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 0, d
+	0x42: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 0, e
+	0x43: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 0, h
+	0x44: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 0, l
+	0x45: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 0, [hl]
+	0x46: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 12},
+	// bit 0, a
+	0x47: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 1, b
+	0x48: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 1, c
+	0x49: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 1, d
+	0x4A: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 1, e
+	0x4B: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 1, h
+	0x4C: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 1, l
+	0x4D: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 1, [hl]
+	0x4E: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 12},
+	// bit 1, a
+	0x4F: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 2, b
+	0x50: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 2, c
+	0x51: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 2, d
+	0x52: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 2, e
+	0x53: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 2, h
+	0x54: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 2, l
+	0x55: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 2, [hl]
+	0x56: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 12},
+	// bit 2, a
+	0x57: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 3, b
+	0x58: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 3, c
+	0x59: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 3, d
+	0x5A: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 3, e
+	0x5B: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 3, h
+	0x5C: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 3, l
+	0x5D: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 3, [hl]
+	0x5E: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 12},
+	// bit 3, a
+	0x5F: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 4, b
+	0x60: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 4, c
+	0x61: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 4, d
+	0x62: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 4, e
+	0x63: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 4, h
+	0x64: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 4, l
+	0x65: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 4, [hl]
+	0x66: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 12},
+	// bit 4, a
+	0x67: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 5, b
+	0x68: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 5, c
+	0x69: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 5, d
+	0x6A: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 5, e
+	0x6B: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 5, h
+	0x6C: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 5, l
+	0x6D: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 5, [hl]
+	0x6E: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 12},
+	// bit 5, a
+	0x6F: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 6, b
+	0x70: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 6, c
+	0x71: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 6, d
+	0x72: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 6, e
+	0x73: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 6, h
+	0x74: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 6, l
+	0x75: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 6, [hl]
+	0x76: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 12},
+	// bit 6, a
+	0x77: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 7, b
+	0x78: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 7, c
+	0x79: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 7, d
+	0x7A: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 7, e
+	0x7B: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 7, h
+	0x7C: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 7, l
+	0x7D: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// bit 7, [hl]
+	0x7E: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 12},
+	// bit 7, a
+	0x7F: {opcode: OP_BIT, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 0, b
+	0x80: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 0, c
+	0x81: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 0, d
+	0x82: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 0, e
+	0x83: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 0, h
+	0x84: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 0, l
+	0x85: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 0, [hl]
+	0x86: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// res 0, a
+	0x87: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 1, b
+	0x88: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 1, c
+	0x89: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 1, d
+	0x8A: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 1, e
+	0x8B: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 1, h
+	0x8C: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 1, l
+	0x8D: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 1, [hl]
+	0x8E: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// res 1, a
+	0x8F: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 2, b
+	0x90: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 2, c
+	0x91: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 2, d
+	0x92: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 2, e
+	0x93: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 2, h
+	0x94: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 2, l
+	0x95: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 2, [hl]
+	0x96: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// res 2, a
+	0x97: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 3, b
+	0x98: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 3, c
+	0x99: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 3, d
+	0x9A: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 3, e
+	0x9B: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 3, h
+	0x9C: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 3, l
+	0x9D: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 3, [hl]
+	0x9E: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// res 3, a
+	0x9F: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 4, b
+	0xA0: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 4, c
+	0xA1: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 4, d
+	0xA2: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 4, e
+	0xA3: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 4, h
+	0xA4: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 4, l
+	0xA5: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 4, [hl]
+	0xA6: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// res 4, a
+	0xA7: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 5, b
+	0xA8: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 5, c
+	0xA9: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 5, d
+	0xAA: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 5, e
+	0xAB: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 5, h
+	0xAC: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 5, l
+	0xAD: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 5, [hl]
+	0xAE: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// res 5, a
+	0xAF: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 6, b
+	0xB0: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 6, c
+	0xB1: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 6, d
+	0xB2: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 6, e
+	0xB3: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 6, h
+	0xB4: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 6, l
+	0xB5: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 6, [hl]
+	0xB6: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// res 6, a
+	0xB7: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 7, b
+	0xB8: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 7, c
+	0xB9: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 7, d
+	0xBA: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 7, e
+	0xBB: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 7, h
+	0xBC: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 7, l
+	0xBD: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// res 7, [hl]
+	0xBE: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// res 7, a
+	0xBF: {opcode: OP_RES, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 0, b
+	0xC0: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 0, c
+	0xC1: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 0, d
+	0xC2: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 0, e
+	0xC3: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 0, h
+	0xC4: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 0, l
+	0xC5: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 0, [hl]
+	0xC6: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// set 0, a
+	0xC7: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 0}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 1, b
+	0xC8: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 1, c
+	0xC9: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 1, d
+	0xCA: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 1, e
+	0xCB: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 1, h
+	0xCC: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 1, l
+	0xCD: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 1, [hl]
+	0xCE: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// set 1, a
+	0xCF: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 1}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 2, b
+	0xD0: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 2, c
+	0xD1: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 2, d
+	0xD2: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 2, e
+	0xD3: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 2, h
+	0xD4: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 2, l
+	0xD5: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 2, [hl]
+	0xD6: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// set 2, a
+	0xD7: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 2}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 3, b
+	0xD8: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 3, c
+	0xD9: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 3, d
+	0xDA: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 3, e
+	0xDB: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 3, h
+	0xDC: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 3, l
+	0xDD: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 3, [hl]
+	0xDE: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// set 3, a
+	0xDF: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 3}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 4, b
+	0xE0: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 4, c
+	0xE1: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 4, d
+	0xE2: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 4, e
+	0xE3: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 4, h
+	0xE4: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 4, l
+	0xE5: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 4, [hl]
+	0xE6: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// set 4, a
+	0xE7: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 4}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 5, b
+	0xE8: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 5, c
+	0xE9: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 5, d
+	0xEA: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 5, e
+	0xEB: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 5, h
+	0xEC: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 5, l
+	0xED: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 5, [hl]
+	0xEE: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// set 5, a
+	0xEF: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 5}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 6, b
+	0xF0: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 6, c
+	0xF1: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 6, d
+	0xF2: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 6, e
+	0xF3: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 6, h
+	0xF4: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 6, l
+	0xF5: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 6, [hl]
+	0xF6: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// set 6, a
+	0xF7: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 6}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 7, b
+	0xF8: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_B}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 7, c
+	0xF9: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_C}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 7, d
+	0xFA: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_D}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 7, e
+	0xFB: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_E}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 7, h
+	0xFC: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_H}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 7, l
+	0xFD: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_L}, ENCODING_NONE}}, length: 2, cycles: 8},
+	// set 7, [hl]
+	0xFE: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_HL, Indirect: true}, ENCODING_NONE}}, length: 2, cycles: 16},
+	// set 7, a
+	0xFF: {opcode: OP_SET, operands: []operandSpec{{Operand{Kind: OPERAND_BIT, Value: 7}, ENCODING_NONE}, {Operand{Kind: OPERAND_REG, Register: REG_A}, ENCODING_NONE}}, length: 2, cycles: 8},
+}
@@ -1,6 +1,7 @@
 package cartridge
 
 import (
+	"errors"
 	"testing"
 )
 
@@ -108,7 +109,6 @@ func Test_SingleByteInstructions(t *testing.T) {
 		{0b00101111, "cpl"},
 		{0b00110111, "scf"},
 		{0b00111111, "ccf"},
-		{0b00010000, "stop"},
 		{0b01110110, "halt"},
 		{0b11001001, "ret"},
 		{0b11011001, "reti"},
@@ -130,3 +130,116 @@ func Test_SingleByteInstructions(t *testing.T) {
 		}
 	}
 }
+
+func Test_TwoByteInstructions(t *testing.T) {
+	table := []struct {
+		input []byte
+		str   string
+	}{
+		{[]byte{0b00010000, 0x00}, "stop"},
+		{[]byte{0b00000110, 0x2A}, "ld b, 42"},
+		{[]byte{0b11100110, 0x0F}, "and a, 15"},
+		{[]byte{0b11101000, 0xFE}, "add sp, -2"},
+		{[]byte{0xCB, 0b01111100}, "bit 7, h"},
+	}
+	for _, check := range table {
+		str, length := dissassembleNextBytes(check.input)
+		if length != 2 {
+			t.Errorf("Expected length for %X to be 2 but found %d", check.input, length)
+		}
+		if str != check.str {
+			t.Errorf("Expected str for %X to be %s but found %s", check.input, check.str, str)
+		}
+	}
+}
+
+func Test_DecodeStructuredInstruction(t *testing.T) {
+	ins, err := Decode([]byte{0x20, 0xFB, 0x00}, 0x150)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ins.Offset != 0x150 {
+		t.Errorf("Expected offset 0x150 but found %x", ins.Offset)
+	}
+	if ins.Opcode != OP_JR {
+		t.Errorf("Expected opcode jr but found %s", ins.Opcode)
+	}
+	if ins.Length != 2 || len(ins.Bytes) != 2 {
+		t.Errorf("Expected length 2 but found %d with bytes %x", ins.Length, ins.Bytes)
+	}
+	if ins.Cycles != 8 || ins.BranchCycles != 12 {
+		t.Errorf("Expected cycles 8/12 but found %d/%d", ins.Cycles, ins.BranchCycles)
+	}
+	if len(ins.Operands) != 2 {
+		t.Fatalf("Expected 2 operands but found %d", len(ins.Operands))
+	}
+	if ins.Operands[0].Kind != OPERAND_COND || ins.Operands[0].Cond != COND_NZ {
+		t.Errorf("Expected nz condition but found %v", ins.Operands[0])
+	}
+	if ins.Operands[1].Kind != OPERAND_OFFSET || ins.Operands[1].Value != -5 {
+		t.Errorf("Expected signed offset -5 but found %v", ins.Operands[1])
+	}
+	if ins.String() != "jr nz, 333" {
+		t.Errorf("Expected str to be jr nz, 333 but found %s", ins.String())
+	}
+}
+
+func Test_HardwareRegisterSymbols(t *testing.T) {
+	table := []struct {
+		input   []byte
+		str     string
+		comment string
+	}{
+		{[]byte{0xE0, 0x40}, "ldh [rLCDC], a", ""},
+		{[]byte{0xF0, 0x44}, "ldh a, [rLY]", ""},
+		{[]byte{0xE0, 0x80}, "ldh [128], a", "HRAM"},
+		{[]byte{0xEA, 0x26, 0xFF}, "ld [rNR52], a", ""},
+		{[]byte{0xEA, 0x00, 0xC0}, "ld [49152], a", "WRAM0"},
+		{[]byte{0x21, 0x00, 0x98}, "ld hl, 38912", "VRAM"},
+		{[]byte{0x21, 0x00, 0xFE}, "ld hl, 65024", "OAM"},
+		{[]byte{0x21, 0x00, 0x40}, "ld hl, 16384", ""},
+		{[]byte{0xCD, 0x00, 0xC0}, "call 49152", ""},
+	}
+	for _, check := range table {
+		ins, err := Decode(check.input, 0)
+		if err != nil {
+			t.Errorf("Unable to decode %x: %s", check.input, err)
+			continue
+		}
+		if ins.String() != check.str {
+			t.Errorf("Expected str for %x to be %s but found %s", check.input, check.str, ins.String())
+		}
+		if ins.Comment() != check.comment {
+			t.Errorf("Expected comment for %x to be %s but found %s", check.input, check.comment, ins.Comment())
+		}
+	}
+}
+
+func Test_DecodeTruncatedInstruction(t *testing.T) {
+	table := []struct {
+		input  []byte
+		needed int
+		str    string
+	}{
+		{[]byte{}, 1, ""},
+		{[]byte{0xCB}, 2, "db 203"},
+		{[]byte{0x3E}, 2, "db 62"},
+		{[]byte{0xC3, 0x50}, 3, "db 195, 80"},
+	}
+	for _, check := range table {
+		_, err := Decode(check.input, 0x7FFF)
+		var truncated *TruncatedInstructionError
+		if !errors.As(err, &truncated) || !errors.Is(err, ErrTruncatedInstruction) {
+			t.Errorf("Expected a truncated instruction error for %x but found %v", check.input, err)
+			continue
+		}
+		if truncated.Offset != 0x7FFF || truncated.Needed != check.needed || truncated.Available != len(check.input) {
+			t.Errorf("Expected %x to need %d bytes at 0x7FFF but found %+v", check.input, check.needed, truncated)
+		}
+		str, length := dissassembleNextBytes(check.input)
+		if str != check.str || length != len(check.input) {
+			t.Errorf("Expected %x to render as %q but found %q with length %d", check.input, check.str, str, length)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package cartridge
+
+import (
+	_ "embed"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//go:embed opcodes.txt
+var opcodeReferenceText string
+
+// OpcodeInfo describes an opcode as listed in the reference table. Mnemonic uses n8 and
+// n16 for immediates, a8 and a16 for addresses and e8 for signed offsets. Cycles are
+// T-cycles, for conditional instructions Cycles is the cost when the branch is not taken
+// and BranchCycles when it is. Flags gives the effect on Z, N, H and C in that order.
+type OpcodeInfo struct {
+	Prefixed     bool
+	Code         byte
+	Mnemonic     string
+	Length       int
+	Cycles       int
+	BranchCycles int
+	Flags        string
+}
+
+// Valid reports whether the opcode is one the CPU implements
+func (o OpcodeInfo) Valid() bool {
+	return o.Mnemonic != "-"
+}
+
+// opcodeReference holds the 256 base opcodes followed by the 256 prefixed by 0xCB
+var opcodeReference = parseOpcodeReference(opcodeReferenceText)
+
+// Reference returns the reference table entry for an opcode, prefixed selects the
+// opcodes which follow 0xCB
+func Reference(prefixed bool, code byte) OpcodeInfo {
+	if prefixed {
+		return opcodeReference[0x100+int(code)]
+	}
+	return opcodeReference[code]
+}
+
+func parseOpcodeReference(text string) [0x200]OpcodeInfo {
+	var table [0x200]OpcodeInfo
+	seen := 0
+	for i, line := range strings.Split(text, "\n") {
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		info, err := parseOpcodeInfo(line)
+		if err != nil {
+			//The table is embedded so a bad line can only come from editing it
+			panic(fmt.Sprintf("opcodes.txt:%d: %s", i+1, err))
+		}
+		index := int(info.Code)
+		if info.Prefixed {
+			index += 0x100
+		}
+		table[index] = info
+		seen++
+	}
+	if seen != len(table) {
+		panic(fmt.Sprintf("opcodes.txt: expected %d opcodes but found %d", len(table), seen))
+	}
+	return table
+}
+
+// parseOpcodeInfo parses a tab separated opcode, length, cycles, flags and mnemonic
+func parseOpcodeInfo(line string) (OpcodeInfo, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != 5 {
+		return OpcodeInfo{}, fmt.Errorf("expected 5 fields but found %d", len(fields))
+	}
+
+	var info OpcodeInfo
+	code := fields[0]
+	if len(code) == 4 && strings.HasPrefix(code, "CB") {
+		info.Prefixed = true
+		code = code[2:]
+	}
+	value, err := strconv.ParseUint(code, 16, 8)
+	if err != nil {
+		return OpcodeInfo{}, fmt.Errorf("invalid opcode %q", fields[0])
+	}
+	info.Code = byte(value)
+
+	if info.Length, err = strconv.Atoi(fields[1]); err != nil {
+		return OpcodeInfo{}, fmt.Errorf("invalid length %q", fields[1])
+	}
+
+	//Conditional instructions list the taken cost then the not taken cost
+	taken, notTaken, conditional := strings.Cut(fields[2], "/")
+	if info.Cycles, err = strconv.Atoi(taken); err != nil {
+		return OpcodeInfo{}, fmt.Errorf("invalid cycles %q", fields[2])
+	}
+	if conditional {
+		info.BranchCycles = info.Cycles
+		if info.Cycles, err = strconv.Atoi(notTaken); err != nil {
+			return OpcodeInfo{}, fmt.Errorf("invalid cycles %q", fields[2])
+		}
+	}
+
+	if len(fields[3]) != 4 {
+		return OpcodeInfo{}, fmt.Errorf("invalid flags %q", fields[3])
+	}
+	info.Flags = fields[3]
+	info.Mnemonic = fields[4]
+	return info, nil
+}
@@ -0,0 +1,245 @@
+package cartridge
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// template renders a decoded operand the way the reference table writes it, with the
+// value replaced by its placeholder
+func template(ins Instruction, o Operand) string {
+	switch o.Kind {
+	case OPERAND_REG:
+		if o.Indirect {
+			return "[" + o.Register.String() + "]"
+		}
+		return o.Register.String()
+	case OPERAND_COND:
+		return o.Cond.String()
+	case OPERAND_IMM8:
+		if o.Indirect {
+			return "[a8]"
+		}
+		return "n8"
+	case OPERAND_IMM16:
+		if o.Indirect {
+			return "[a16]"
+		}
+		if ins.Opcode == OP_JP || ins.Opcode == OP_CALL {
+			return "a16"
+		}
+		return "n16"
+	case OPERAND_OFFSET, OPERAND_SIMM8:
+		return "e8"
+	case OPERAND_SP_OFFSET:
+		return "sp + e8"
+	case OPERAND_VECTOR:
+		return fmt.Sprintf("$%02X", o.Value)
+	}
+	return fmt.Sprintf("%d", o.Value)
+}
+
+// Every operand byte is 0xFE so the decoded values show whether they were read as signed
+func checkAgainstReference(t *testing.T, input []byte, want OpcodeInfo) {
+	t.Helper()
+	ins, err := Decode(input, 0)
+	if err != nil {
+		t.Errorf("Unable to decode %X: %s", input, err)
+		return
+	}
+
+	mnemonic := "-"
+	if ins.Opcode != OP_INVALID {
+		operands := make([]string, len(ins.Operands))
+		for i, op := range ins.Operands {
+			operands[i] = template(ins, op)
+		}
+		mnemonic = Formatter{}.join(ins.Opcode.String(), operands)
+	}
+	if mnemonic != want.Mnemonic {
+		t.Errorf("Expected %X to decode as %s but found %s", input, want.Mnemonic, mnemonic)
+	}
+	if ins.Length != want.Length {
+		t.Errorf("Expected length of %s to be %d but found %d", want.Mnemonic, want.Length, ins.Length)
+	}
+	if ins.Cycles != want.Cycles || ins.BranchCycles != want.BranchCycles {
+		t.Errorf("Expected cycles of %s to be %d/%d but found %d/%d", want.Mnemonic, want.Cycles, want.BranchCycles, ins.Cycles, ins.BranchCycles)
+	}
+
+	for _, op := range ins.Operands {
+		expected, ok := map[OperandKind]int{
+			OPERAND_IMM8:      0xFE,
+			OPERAND_IMM16:     0xFEFE,
+			OPERAND_OFFSET:    -2,
+			OPERAND_SIMM8:     -2,
+			OPERAND_SP_OFFSET: -2,
+		}[op.Kind]
+		if ok && op.Value != expected {
+			t.Errorf("Expected operand %s of %s to be %d but found %d", template(ins, op), want.Mnemonic, expected, op.Value)
+		}
+	}
+}
+
+func Test_DecodeMatchesReference(t *testing.T) {
+	for code := 0; code <= 0xFF; code++ {
+		if code == 0xCB {
+			continue
+		}
+		checkAgainstReference(t, []byte{byte(code), 0xFE, 0xFE}, Reference(false, byte(code)))
+	}
+	for code := 0; code <= 0xFF; code++ {
+		checkAgainstReference(t, []byte{0xCB, byte(code)}, Reference(true, byte(code)))
+	}
+}
+
+func Test_ReferenceTable(t *testing.T) {
+	invalid := 0
+	for i := 0; i < 0x200; i++ {
+		info := Reference(i >= 0x100, byte(i))
+		if int(info.Code) != i&0xFF || info.Prefixed != (i >= 0x100) {
+			t.Errorf("Expected entry %03X to be for its own opcode but found %v", i, info)
+		}
+		for j, flag := range info.Flags {
+			if !strings.ContainsRune("01-", flag) && flag != rune("ZNHC"[j]) {
+				t.Errorf("Unexpected flag %c for %s", flag, info.Mnemonic)
+			}
+		}
+		if !info.Valid() {
+			invalid++
+		}
+	}
+	//0xD3, 0xDB, 0xDD, 0xE3, 0xE4, 0xEB, 0xEC, 0xED, 0xF4, 0xFC, 0xFD
+	if invalid != 11 {
+		t.Errorf("Expected 11 invalid opcodes but found %d", invalid)
+	}
+}
+
+func Test_SignedOperands(t *testing.T) {
+	table := []struct {
+		input []byte
+		str   string
+		rgbds string
+	}{
+		{[]byte{0xE8, 0xFE}, "add sp, -2", "add sp, -$02"},
+		{[]byte{0xE8, 0x10}, "add sp, 16", "add sp, $10"},
+		{[]byte{0xF8, 0xFE}, "ld hl, sp - 2", "ld hl, sp - $02"},
+		{[]byte{0xF8, 0x02}, "ld hl, sp + 2", "ld hl, sp + $02"},
+		{[]byte{0x10, 0x00}, "stop", "stop"},
+		{[]byte{0x10, 0x01}, "db 16, 1 ; stop", "db $10, $01 ; stop"},
+	}
+	for _, check := range table {
+		ins, err := Decode(check.input, 0)
+		if err != nil {
+			t.Errorf("Unable to decode %X: %s", check.input, err)
+			continue
+		}
+		if ins.String() != check.str {
+			t.Errorf("Expected %X to be %s but found %s", check.input, check.str, ins.String())
+		}
+		if str := rgbdsFormatter().Instruction(ins); str != check.rgbds {
+			t.Errorf("Expected %X to be %s for rgbds but found %s", check.input, check.rgbds, str)
+		}
+	}
+}
+
+// The decoder is generated from opcodes.txt, so a wrong row would also be wrong in
+// Reference. These encodings are written out by hand from the Pan Docs instead.
+func Test_DecodeKnownEncodings(t *testing.T) {
+	type encoding struct {
+		input        []byte
+		str          string
+		length       int
+		cycles       int
+		branchCycles int
+	}
+	table := []encoding{
+		{[]byte{0x00}, "nop", 1, 4, 0},
+		{[]byte{0x01, 0x34, 0x12}, "ld bc, 4660", 3, 12, 0},
+		{[]byte{0x03}, "inc bc", 1, 8, 0},
+		{[]byte{0x08, 0x00, 0xC0}, "ld [49152], sp", 3, 20, 0},
+		{[]byte{0x09}, "add hl, bc", 1, 8, 0},
+		{[]byte{0x19}, "add hl, de", 1, 8, 0},
+		{[]byte{0x29}, "add hl, hl", 1, 8, 0},
+		{[]byte{0x39}, "add hl, sp", 1, 8, 0},
+		{[]byte{0x10, 0x00}, "stop", 2, 4, 0},
+		{[]byte{0x18, 0xFE}, "jr 0", 2, 12, 0},
+		{[]byte{0x20, 0xFE}, "jr nz, 0", 2, 8, 12},
+		{[]byte{0x22}, "ld [hl+], a", 1, 8, 0},
+		{[]byte{0x3A}, "ld a, [hl-]", 1, 8, 0},
+		{[]byte{0x36, 0x05}, "ld [hl], 5", 2, 12, 0},
+		{[]byte{0x76}, "halt", 1, 4, 0},
+		{[]byte{0x86}, "add a, [hl]", 1, 8, 0},
+		{[]byte{0xC0}, "ret nz", 1, 8, 20},
+		{[]byte{0xC1}, "pop bc", 1, 12, 0},
+		{[]byte{0xC2, 0x00, 0x02}, "jp nz, 512", 3, 12, 16},
+		{[]byte{0xC3, 0x00, 0x02}, "jp 512", 3, 16, 0},
+		{[]byte{0xC4, 0x00, 0x02}, "call nz, 512", 3, 12, 24},
+		{[]byte{0xC5}, "push bc", 1, 16, 0},
+		{[]byte{0xC9}, "ret", 1, 16, 0},
+		{[]byte{0xCD, 0x00, 0x02}, "call 512", 3, 24, 0},
+		{[]byte{0xD9}, "reti", 1, 16, 0},
+		{[]byte{0xE0, 0x80}, "ldh [128], a", 2, 12, 0},
+		{[]byte{0xE2}, "ldh [c], a", 1, 8, 0},
+		{[]byte{0xE6, 0x0F}, "and a, 15", 2, 8, 0},
+		{[]byte{0xE8, 0xFE}, "add sp, -2", 2, 16, 0},
+		{[]byte{0xE9}, "jp hl", 1, 4, 0},
+		{[]byte{0xEA, 0x00, 0xC0}, "ld [49152], a", 3, 16, 0},
+		{[]byte{0xF0, 0x80}, "ldh a, [128]", 2, 12, 0},
+		{[]byte{0xF1}, "pop af", 1, 12, 0},
+		{[]byte{0xF3}, "di", 1, 4, 0},
+		{[]byte{0xF8, 0xFE}, "ld hl, sp - 2", 2, 12, 0},
+		{[]byte{0xF9}, "ld sp, hl", 1, 8, 0},
+		{[]byte{0xFA, 0x00, 0xC0}, "ld a, [49152]", 3, 16, 0},
+		{[]byte{0xFB}, "ei", 1, 4, 0},
+		{[]byte{0xCB, 0x00}, "rlc b", 2, 8, 0},
+		{[]byte{0xCB, 0x06}, "rlc [hl]", 2, 16, 0},
+		{[]byte{0xCB, 0x37}, "swap a", 2, 8, 0},
+	}
+
+	//The rst vectors are every 8 bytes from 0xC7
+	for v := 0; v < 8; v++ {
+		table = append(table, encoding{[]byte{byte(0xC7 + v*8)}, fmt.Sprintf("rst %d", v*8), 1, 16, 0})
+	}
+
+	//ld r8, r8 and the cb bit operations select registers in this order, [hl] takes longer
+	registers := []string{"b", "c", "d", "e", "h", "l", "[hl]", "a"}
+	for dst, d := range registers {
+		for src, s := range registers {
+			if d == "[hl]" && s == "[hl]" {
+				continue
+			}
+			cycles := 4
+			if d == "[hl]" || s == "[hl]" {
+				cycles = 8
+			}
+			table = append(table, encoding{[]byte{byte(0x40 | dst<<3 | src)}, fmt.Sprintf("ld %s, %s", d, s), 1, cycles, 0})
+		}
+	}
+	for bit := 0; bit < 8; bit++ {
+		for r, name := range registers {
+			bitCycles, setCycles := 8, 8
+			if name == "[hl]" {
+				bitCycles, setCycles = 12, 16
+			}
+			table = append(table,
+				encoding{[]byte{0xCB, byte(0x40 | bit<<3 | r)}, fmt.Sprintf("bit %d, %s", bit, name), 2, bitCycles, 0},
+				encoding{[]byte{0xCB, byte(0x80 | bit<<3 | r)}, fmt.Sprintf("res %d, %s", bit, name), 2, setCycles, 0},
+				encoding{[]byte{0xCB, byte(0xC0 | bit<<3 | r)}, fmt.Sprintf("set %d, %s", bit, name), 2, setCycles, 0})
+		}
+	}
+
+	for _, check := range table {
+		ins, err := Decode(check.input, 0)
+		if err != nil {
+			t.Errorf("Unable to decode %X: %s", check.input, err)
+			continue
+		}
+		if ins.String() != check.str {
+			t.Errorf("Expected %X to be %s but found %s", check.input, check.str, ins.String())
+		}
+		if ins.Length != check.length || ins.Cycles != check.cycles || ins.BranchCycles != check.branchCycles {
+			t.Errorf("Expected %s to be %d bytes and %d/%d cycles but found %d bytes and %d/%d", check.str, check.length, check.cycles, check.branchCycles, ins.Length, ins.Cycles, ins.BranchCycles)
+		}
+	}
+}
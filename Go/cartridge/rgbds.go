@@ -0,0 +1,197 @@
+package cartridge
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Runs of unreached bytes at least this long are written to their own file and INCBIN'd
+const INCBIN_THRESHOLD = 256
+
+// RGBDSProject is a set of files which rgbasm and rgblink assemble back into the original ROM
+type RGBDSProject struct {
+	Main  string
+	Files map[string][]byte
+}
+
+// Write saves every file of the project into dir, creating it if needed
+func (p RGBDSProject) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for name, contents := range p.Files {
+		err := os.WriteFile(filepath.Join(dir, name), contents, 0o644)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Header fields written out as data, in the order they appear in the header, with
+// a description of their value for the hexdump
+var headerFields = []struct {
+	name  string
+	start int
+	end   int
+	value func(c *Cartridge) string
+}{
+	{"Nintendo logo", NINTENDO_LOGO_START, 0x0133, func(c *Cartridge) string {
+		if validateNintendoLogo(c.rom) {
+			return "valid"
+		}
+		return "invalid"
+	}},
+	{"Title", TITLE_START, MANUFACTUTURER_CODE_START - 1, func(c *Cartridge) string {
+		return fmt.Sprintf("%q", c.Header.Title11)
+	}},
+	{"Manufacturer code", MANUFACTUTURER_CODE_START, MANUFACTUTURER_CODE_END, func(c *Cartridge) string {
+		return fmt.Sprintf("%q", c.Header.ManufacturerCode)
+	}},
+	{"CGB flag", CGB_FLAG, CGB_FLAG, func(c *Cartridge) string {
+		return c.Header.CGB.String()
+	}},
+	{"New licensee code", NEW_LICENSEE_CODE_START, NEW_LICENSEE_CODE_END, func(c *Cartridge) string {
+		return fmt.Sprintf("%q", c.Header.NewLicenseeCode)
+	}},
+	{"SGB flag", SGB_FLAG, SGB_FLAG, func(c *Cartridge) string {
+		return yesNo(c.Header.SGB)
+	}},
+	{"Cartridge type", CARTRIDGE_TYPE, CARTRIDGE_TYPE, func(c *Cartridge) string {
+		return c.Header.CartridgeType.Name()
+	}},
+	{"ROM size", ROM_SIZE, ROM_SIZE, func(c *Cartridge) string {
+		return fmt.Sprintf("%d KiB, %d banks", c.Header.ROMSize/1024, c.Header.ROMBanks)
+	}},
+	{"RAM size", RAM_SIZE, RAM_SIZE, func(c *Cartridge) string {
+		return fmt.Sprintf("%d KiB, %d banks", c.Header.RAMSize/1024, c.Header.RAMBanks)
+	}},
+	{"Destination code", DESTINATION_CODE, DESTINATION_CODE, func(c *Cartridge) string {
+		return c.Header.Destination()
+	}},
+	{"Old licensee code", OLD_LICENSEE_CODE, OLD_LICENSEE_CODE, func(c *Cartridge) string {
+		return c.Header.Licensee()
+	}},
+	{"Mask ROM version", MASK_ROM_VERSION, MASK_ROM_VERSION, func(c *Cartridge) string {
+		return fmt.Sprintf("%d", c.Header.MaskROMVersion)
+	}},
+	{"Header checksum", HEADER_CHECKSUM, HEADER_CHECKSUM, func(c *Cartridge) string {
+		return checksumStatus(int(c.Header.HeaderChecksum), int(computeHeaderChecksum(c.rom)), 2)
+	}},
+	{"Global checksum", GLOBAL_CHECKSUM_START, GLOBAL_CHECKSUM_END, func(c *Cartridge) string {
+		return checksumStatus(int(c.Header.GlobalChecksum), int(computeGlobalChecksum(c.rom)), 4)
+	}},
+}
+
+func checksumStatus(stored, expected, digits int) string {
+	if stored == expected {
+		return "valid"
+	}
+	return fmt.Sprintf("invalid, expected $%0*X", digits, expected)
+}
+
+// RGBDS writes the ROM out as an rgbasm project named name. Reached code is written as
+// instructions with labels, anything else is written as data so the ROM reassembles byte for byte.
+func (c *Cartridge) RGBDS(name string) RGBDSProject {
+	project := RGBDSProject{Main: name + ".asm", Files: map[string][]byte{}}
+	f := rgbdsFormatter().withContext(c.labels, c.targets, nil)
+
+	byOffset := make(map[int]Instruction, len(c.instructions))
+	for _, ins := range c.instructions {
+		byOffset[ins.Offset] = ins
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "; %s\n", strings.TrimRight(c.Title, "\x00"))
+	fmt.Fprintf(&builder, "; rgbasm -o %s.o %s.asm && rgblink -o %s.gb %s.o\n\n", name, name, name, name)
+
+	addresses := make([]int, 0, len(hardwareRegisters))
+	for address := range hardwareRegisters {
+		addresses = append(addresses, address)
+	}
+	sort.Ints(addresses)
+	for _, address := range addresses {
+		fmt.Fprintf(&builder, "DEF %s EQU $%04X\n", hardwareRegisters[address], address)
+	}
+
+	for bankStart := 0; bankStart < len(c.rom); bankStart += BANK_SIZE {
+		bankEnd := min(bankStart+BANK_SIZE, len(c.rom))
+		bank := OffsetLocation(bankStart)
+		if bank.Bank == 0 {
+			fmt.Fprintf(&builder, "\nSECTION \"ROM Bank $000\", ROM0[$0000]\n\n")
+		} else {
+			fmt.Fprintf(&builder, "\nSECTION \"ROM Bank $%03X\", ROMX[$4000], BANK[$%X]\n\n", bank.Bank, bank.Bank)
+		}
+
+		for offset := bankStart; offset < bankEnd; {
+			if offset == NINTENDO_LOGO_START {
+				c.writeHeader(&builder)
+				offset = GLOBAL_CHECKSUM_END + 1
+				continue
+			}
+
+			if ins, ok := byOffset[offset]; ok && offset+ins.Length <= bankEnd {
+				if label, ok := c.labels[ins.Location()]; ok {
+					fmt.Fprintf(&builder, "%s:\n", label)
+				}
+				builder.WriteString("\t")
+				builder.WriteString(f.Instruction(ins))
+				if comment := ins.Comment(); comment != "" {
+					builder.WriteString(" ; ")
+					builder.WriteString(comment)
+				}
+				builder.WriteRune('\n')
+				offset += ins.Length
+				continue
+			}
+
+			//An instruction running past the end of the bank is written as data, other code may still refer to its label
+			if label, ok := c.labels[OffsetLocation(offset)]; ok {
+				fmt.Fprintf(&builder, "%s:\n", label)
+			}
+			end := offset + 1
+			for end < bankEnd && end != NINTENDO_LOGO_START {
+				if _, ok := byOffset[end]; ok {
+					break
+				}
+				end++
+			}
+
+			if end-offset >= INCBIN_THRESHOLD {
+				loc := OffsetLocation(offset)
+				file := fmt.Sprintf("%s_%02X_%04X.bin", name, loc.Bank, loc.Address)
+				project.Files[file] = c.rom[offset:end]
+				fmt.Fprintf(&builder, "\tINCBIN \"%s\" ; %s-%s\n", file, loc, OffsetLocation(end-1))
+			} else {
+				writeData(&builder, c.rom[offset:end])
+			}
+			offset = end
+		}
+	}
+
+	project.Files[project.Main] = []byte(builder.String())
+	return project
+}
+
+func (c *Cartridge) writeHeader(builder *strings.Builder) {
+	for _, field := range headerFields {
+		if field.end >= len(c.rom) {
+			return
+		}
+		fmt.Fprintf(builder, "; %s\n", field.name)
+		writeData(builder, c.rom[field.start:field.end+1])
+	}
+}
+
+// writeData writes bytes as db lines of up to 16 values
+func writeData(builder *strings.Builder, bytes []byte) {
+	for start := 0; start < len(bytes); start += 16 {
+		end := min(start+16, len(bytes))
+		builder.WriteRune('\t')
+		builder.WriteString(rgbdsFormatter().data(bytes[start:end]))
+		builder.WriteRune('\n')
+	}
+}
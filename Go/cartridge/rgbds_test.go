@@ -0,0 +1,74 @@
+package cartridge_test
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/assembler"
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// roundTrip exports rom as an RGBDS project, assembles it and checks the output matches
+func roundTrip(t *testing.T, rom []byte) cartridge.RGBDSProject {
+	t.Helper()
+	c, err := cartridge.New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	project := c.RGBDS("example")
+	asm := assembler.Assembler{ReadFile: func(name string) ([]byte, error) {
+		contents, ok := project.Files[name]
+		if !ok {
+			return nil, fmt.Errorf("no file %s in project", name)
+		}
+		return contents, nil
+	}}
+
+	output, err := asm.Assemble(string(project.Files[project.Main]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output) != len(rom) {
+		t.Fatalf("Expected %d bytes but assembled %d", len(rom), len(output))
+	}
+	if !bytes.Equal(output, rom) {
+		for i := range rom {
+			if rom[i] != output[i] {
+				t.Fatalf("Expected byte %02X at offset %x but found %02X", rom[i], i, output[i])
+			}
+		}
+	}
+	return project
+}
+
+func Test_RGBDSRoundTrip(t *testing.T) {
+	rom, err := os.ReadFile("../example/example.gb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	roundTrip(t, rom)
+}
+
+// An instruction crossing the end of bank 0 is written as data but keeps its label
+func Test_RGBDSBankBoundary(t *testing.T) {
+	example, err := os.ReadFile("../example/example.gb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rom := make([]byte, 2*cartridge.BANK_SIZE)
+	copy(rom, example[:0x150])
+	copy(rom[0x100:], []byte{0x00, 0xC3, 0x50, 0x01}) // nop, jp $0150
+	copy(rom[0x150:], []byte{0xCD, 0xFE, 0x3F})       // call $3FFE
+	copy(rom[0x153:], []byte{0x18, 0xFE})             // jr $0153
+	copy(rom[0x3FFE:], []byte{0xC3, 0x50, 0x01})      // jp $0150
+
+	project := roundTrip(t, rom)
+	main := string(project.Files[project.Main])
+	if !strings.Contains(main, "call sub_3FFE\n") || !strings.Contains(main, "sub_3FFE:\n\tdb $C3, $50\n") {
+		t.Errorf("Expected the label of the instruction crossing the bank to be kept on its data, found\n%s", main[strings.Index(main, "sub_"):])
+	}
+}
@@ -0,0 +1,77 @@
+package cartridge
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// ParseSymbols reads a symbol file of bank:address name lines, as written by rgblink
+// and read by emulators such as BGB. Comments start with ; and run to the end of the line.
+func ParseSymbols(r io.Reader) (map[Location]string, error) {
+	symbols := map[Location]string{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text, _, _ := strings.Cut(scanner.Text(), ";")
+		fields := strings.Fields(text)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected bank:address name", line)
+		}
+		loc, err := ParseLocation(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if loc.Bank < 0 {
+			return nil, fmt.Errorf("line %d: %s has no bank", line, fields[0])
+		}
+		symbols[loc] = fields[1]
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return symbols, nil
+}
+
+// WriteSymbols writes every label as a symbol file which emulators such as BGB,
+// SameBoy and Emulicious can load, sorted by bank and address
+func (c *Cartridge) WriteSymbols(w io.Writer) error {
+	locations := sortedLocations(c.labels)
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "; File generated by gogb")
+	for _, loc := range locations {
+		fmt.Fprintf(bw, "%s %s\n", loc, c.labels[loc])
+	}
+	return bw.Flush()
+}
+
+// sortedLocations returns the locations which have labels, sorted by bank and address
+func sortedLocations(labels map[Location]string) []Location {
+	locations := make([]Location, 0, len(labels))
+	for loc := range labels {
+		locations = append(locations, loc)
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		if locations[i].Bank != locations[j].Bank {
+			return locations[i].Bank < locations[j].Bank
+		}
+		return locations[i].Address < locations[j].Address
+	})
+	return locations
+}
+
+// indexLabels maps each label back to its location, a name used more than once maps to the
+// first location it is used at
+func indexLabels(labels map[Location]string) map[string]Location {
+	names := make(map[string]Location, len(labels))
+	for _, loc := range sortedLocations(labels) {
+		if _, ok := names[labels[loc]]; !ok {
+			names[labels[loc]] = loc
+		}
+	}
+	return names
+}
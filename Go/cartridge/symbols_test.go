@@ -0,0 +1,134 @@
+package cartridge
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_ParseLocation(t *testing.T) {
+	table := []struct {
+		input    string
+		expected Location
+	}{
+		{"01:4000", Location{Bank: 1, Address: 0x4000}},
+		{"00:0150", Location{Bank: 0, Address: 0x150}},
+		{"$0150", Location{Bank: 0, Address: 0x150}},
+		{"0x4abc", Location{Bank: -1, Address: 0x4ABC}},
+		{"1F:7FFF", Location{Bank: 0x1F, Address: 0x7FFF}},
+	}
+	for _, check := range table {
+		loc, err := ParseLocation(check.input)
+		if err != nil || loc != check.expected {
+			t.Errorf("Expected %s to parse as %v but found %v, %v", check.input, check.expected, loc, err)
+		}
+	}
+	for _, input := range []string{"", "01:", "xx:4000", "10000"} {
+		if _, err := ParseLocation(input); err == nil {
+			t.Errorf("Expected %q to be invalid", input)
+		}
+	}
+}
+
+func Test_SymbolsNameLabels(t *testing.T) {
+	symbols, err := ParseSymbols(strings.NewReader("; File generated by rgblink\n00:0160 Helper\n\n00:0150 Main ; entry\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(symbols) != 2 || symbols[Location{Bank: 0, Address: 0x160}] != "Helper" {
+		t.Fatalf("Unexpected symbols %v", symbols)
+	}
+
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01},       // jp 0x150
+		0x150: {0xCD, 0x60, 0x01, 0x76}, // call 0x160, halt
+		0x160: {0xC9},                   // ret
+	})
+	c, err := New(rom, WithSymbols(symbols))
+	if err != nil {
+		t.Fatal(err)
+	}
+	listing := c.Listing(0x150, 0x161)
+	if !strings.Contains(listing, "Main:\n00:0150 call Helper\n") {
+		t.Errorf("Expected symbols in the listing but found\n%s", listing)
+	}
+
+	for _, input := range []string{"00:0150", "4150 Main", "00:0150 Main extra"} {
+		if _, err := ParseSymbols(strings.NewReader(input)); err == nil {
+			t.Errorf("Expected %q to be invalid", input)
+		}
+	}
+}
+
+func Test_SymbolsSeedEntryPointsAndName(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01},             // jp 0x150
+		0x150: {0x18, 0xFE},                   // jr 0x150
+		0x200: {0xFA, 0x00, 0xC0, 0xE0, 0x80}, // ld a, [0xC000], ldh [0x80], a
+		0x205: {0xC9},                         // ret
+	})
+	symbols := map[Location]string{
+		{Bank: 0, Address: 0x200}:  "Unreachable",
+		{Bank: 1, Address: 0xC000}: "wCounter",
+		{Bank: 0, Address: 0xFF80}: "hTemp",
+	}
+	c, err := New(rom, WithSymbols(symbols))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listing := c.Listing(0x200, 0x206)
+	expected := "Unreachable:\n00:0200 ld a, [wCounter] ; WRAM0\n00:0203 ldh [hTemp], a ; HRAM\n00:0205 ret\n"
+	if listing != expected {
+		t.Errorf("Expected\n%s\nbut found\n%s", expected, listing)
+	}
+
+	var out strings.Builder
+	if err := c.WriteSymbols(&out); err != nil {
+		t.Fatal(err)
+	}
+	exported, err := ParseSymbols(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for loc, name := range symbols {
+		if exported[loc] != name {
+			t.Errorf("Expected %s to be exported as %s but found %q", loc, name, exported[loc])
+		}
+	}
+	if exported[Location{Bank: 0, Address: 0x150}] != "jp_0_0150" {
+		t.Errorf("Expected generated labels to be exported but found\n%s", out.String())
+	}
+	if !strings.HasPrefix(out.String(), "; File generated by gogb\n00:0000 rst_00\n") {
+		t.Errorf("Expected symbols sorted by location but found\n%s", out.String())
+	}
+}
+
+func Test_LookupDuplicateSymbols(t *testing.T) {
+	symbols, err := ParseSymbols(strings.NewReader("01:4000 Init\n00:0160 Init\n02:4000 Init\n00:0150 Main\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rom := newTestROM(0x10000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0x18, 0xFE},       // jr 0x150
+	})
+	c, err := New(rom, WithSymbols(symbols))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loc, ok := c.Lookup("Init"); !ok || loc != (Location{Bank: 0, Address: 0x160}) {
+		t.Errorf("Expected Init to be the first location using it, 00:0160, but found %s", loc)
+	}
+	if loc, ok := c.Lookup("Main"); !ok || loc != (Location{Bank: 0, Address: 0x150}) {
+		t.Errorf("Expected Main at 00:0150 but found %s", loc)
+	}
+	//Map order changes from run to run so index enough times to catch it
+	for i := 0; i < 100; i++ {
+		if loc := indexLabels(symbols)["Init"]; loc != (Location{Bank: 0, Address: 0x160}) {
+			t.Fatalf("Expected Init to always index as 00:0160 but found %s", loc)
+		}
+	}
+	if _, ok := c.Lookup("Missing"); ok {
+		t.Errorf("Expected an unknown name not to be found")
+	}
+}
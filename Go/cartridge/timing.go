@@ -0,0 +1,276 @@
+package cartridge
+
+import "fmt"
+
+// Timing is the range of T-cycles some code can take, from the fastest way through it to the slowest
+type Timing struct {
+	Min int
+	Max int
+}
+
+func (t Timing) add(o Timing) Timing {
+	return Timing{Min: t.Min + o.Min, Max: t.Max + o.Max}
+}
+
+// M converts the timing to M-cycles, each of which is 4 T-cycles
+func (t Timing) M() Timing {
+	return Timing{Min: t.Min / 4, Max: t.Max / 4}
+}
+
+func (t Timing) String() string {
+	if t.Min == t.Max {
+		return fmt.Sprintf("%d", t.Min)
+	}
+	return fmt.Sprintf("%d-%d", t.Min, t.Max)
+}
+
+// Timing returns the T-cycles the instruction takes whether or not it branches
+func (i Instruction) Timing() Timing {
+	if i.BranchCycles == 0 {
+		return Timing{Min: i.Cycles, Max: i.Cycles}
+	}
+	return Timing{Min: min(i.Cycles, i.BranchCycles), Max: max(i.Cycles, i.BranchCycles)}
+}
+
+// cyclesColumn renders the T and M-cycles of an instruction, taken then not taken for conditional branches
+func cyclesColumn(ins Instruction) string {
+	if ins.BranchCycles == 0 {
+		return fmt.Sprintf("%dT %dM", ins.Cycles, ins.Cycles/4)
+	}
+	return fmt.Sprintf("%d/%dT %d/%dM", ins.BranchCycles, ins.Cycles, ins.BranchCycles/4, ins.Cycles/4)
+}
+
+// Timing returns the T-cycles taken to run the block from start to end, not counting
+// any function it calls
+func (b *BasicBlock) Timing() Timing {
+	var t Timing
+	for _, ins := range b.Instructions {
+		t = t.add(ins.Timing())
+	}
+	return t
+}
+
+// Timing estimates how long a function takes from its entry to where it returns, as the
+// shortest and longest paths through its blocks. Called functions are included, loops
+// are counted as running once and recursive calls, back into any function which can
+// call the caller, count as nothing.
+func (g *CFG) Timing(f *Function) Timing {
+	if t, ok := g.timings[f.Entry]; ok {
+		return t
+	}
+	p := &pathTimer{
+		g:        g,
+		f:        f,
+		blocks:   map[Location]*BasicBlock{},
+		visiting: map[Location]bool{},
+		open:     map[int]int{},
+		memo:     map[Location]Timing{},
+	}
+	for _, b := range f.Blocks {
+		p.blocks[b.Start] = b
+	}
+	p.loops = components([]Location{f.Entry}, func(loc Location) []Location {
+		next := []Location{}
+		for _, edge := range p.blocks[loc].Edges {
+			if edge.Kind != EDGE_CALL && p.blocks[edge.To] != nil {
+				next = append(next, edge.To)
+			}
+		}
+		return next
+	})
+	t := p.time(f.Entry)
+	g.timings[f.Entry] = t
+	return t
+}
+
+// callTiming is the timing of a call or tail call made by caller, zero when it recurses
+func (g *CFG) callTiming(caller Location, entry Location) Timing {
+	f, ok := g.function(entry)
+	if !ok || g.recursion[entry] == g.recursion[caller] {
+		return Timing{}
+	}
+	return g.Timing(f)
+}
+
+// recursionComponents numbers the functions so that those which can call each other, directly
+// or through other functions, share a number
+func (g *CFG) recursionComponents() map[Location]int {
+	calls := map[Location][]Location{}
+	for _, f := range g.Functions {
+		for _, b := range f.Blocks {
+			for _, edge := range b.Edges {
+				//Jumps to the start of another function are tail calls
+				if _, ok := g.byEntry[edge.To]; ok && (edge.Kind == EDGE_CALL || edge.To != f.Entry) {
+					calls[f.Entry] = append(calls[f.Entry], edge.To)
+				}
+			}
+		}
+	}
+
+	entries := make([]Location, len(g.Functions))
+	for i, f := range g.Functions {
+		entries[i] = f.Entry
+	}
+	return components(entries, func(loc Location) []Location { return calls[loc] })
+}
+
+// components numbers the strongly connected components of the graph reachable from starts,
+// nodes which can each reach the other share a number
+func components(starts []Location, next func(Location) []Location) map[Location]int {
+	index := map[Location]int{}
+	low := map[Location]int{}
+	onStack := map[Location]bool{}
+	stack := []Location{}
+	component := map[Location]int{}
+
+	var visit func(v Location)
+	visit = func(v Location) {
+		index[v], low[v] = len(index), len(index)
+		stack = append(stack, v)
+		onStack[v] = true
+		for _, w := range next(v) {
+			if _, seen := index[w]; !seen {
+				visit(w)
+				low[v] = min(low[v], low[w])
+			} else if onStack[w] {
+				low[v] = min(low[v], index[w])
+			}
+		}
+		if low[v] != index[v] {
+			return
+		}
+		for {
+			w := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[w] = false
+			component[w] = index[v]
+			if w == v {
+				return
+			}
+		}
+	}
+	for _, start := range starts {
+		if _, seen := index[start]; !seen {
+			visit(start)
+		}
+	}
+	return component
+}
+
+// choice is one way out of a block, costing the last instruction of the block and
+// anything it calls before continuing at next
+type choice struct {
+	cost Timing
+	next *Location
+}
+
+// pathTimer times the paths through the blocks of a function. A path ends at a return, or
+// at a jump back to a block already on it which is counted but not followed again.
+type pathTimer struct {
+	g      *CFG
+	f      *Function
+	blocks map[Location]*BasicBlock
+	//Component of each block, blocks in the same loop share one
+	loops map[Location]int
+	//Blocks on the current path and how many of them are in each loop
+	visiting map[Location]bool
+	open     map[int]int
+	//Timings of blocks entered from outside of their loop, which are the same whichever
+	//path reached them as none of the blocks they can reach are on it
+	memo map[Location]Timing
+}
+
+// time is the timing of the paths from the block at loc to a return
+func (p *pathTimer) time(loc Location) Timing {
+	loop := p.loops[loc]
+	entered := p.open[loop] == 0
+	if t, ok := p.memo[loc]; ok && entered {
+		return t
+	}
+	b := p.blocks[loc]
+	p.visiting[loc] = true
+	p.open[loop]++
+	defer func() {
+		delete(p.visiting, loc)
+		p.open[loop]--
+	}()
+
+	var base Timing
+	for _, ins := range b.Instructions[:len(b.Instructions)-1] {
+		base = base.add(ins.Timing())
+	}
+
+	var best Timing
+	for i, c := range p.g.choices(p.f.Entry, b) {
+		t := c.cost
+		if c.next != nil {
+			switch {
+			case p.visiting[*c.next]:
+				//Loops are counted once
+			case p.blocks[*c.next] != nil:
+				t = t.add(p.time(*c.next))
+			default:
+				//A jump out of the function is a tail call
+				t = t.add(p.g.callTiming(p.f.Entry, *c.next))
+			}
+		}
+		if i == 0 {
+			best = t
+		} else {
+			best = Timing{Min: min(best.Min, t.Min), Max: max(best.Max, t.Max)}
+		}
+	}
+
+	best = base.add(best)
+	if entered {
+		p.memo[loc] = best
+	}
+	return best
+}
+
+// choices lists the ways control can leave a block of the function at caller along with what each costs
+func (g *CFG) choices(caller Location, b *BasicBlock) []choice {
+	last := b.Instructions[len(b.Instructions)-1]
+	var taken, next *Location
+	var called Timing
+	for _, edge := range b.Edges {
+		to := edge.To
+		switch edge.Kind {
+		case EDGE_TAKEN:
+			taken = &to
+		case EDGE_FALLTHROUGH:
+			next = &to
+		case EDGE_CALL:
+			called = g.callTiming(caller, to)
+		}
+	}
+
+	cycles := Timing{Min: last.Cycles, Max: last.Cycles}
+	branch := Timing{Min: last.BranchCycles, Max: last.BranchCycles}
+	conditional := last.BranchCycles != 0
+	if !conditional {
+		branch = cycles
+	}
+
+	switch last.Opcode {
+	case OP_CALL, OP_RST:
+		if conditional {
+			return []choice{{branch.add(called), next}, {cycles, next}}
+		}
+		return []choice{{cycles.add(called), next}}
+	case OP_JP, OP_JR, OP_RET, OP_RETI:
+		//Jumps with an unknown target and returns leave the function
+		if conditional {
+			return []choice{{branch, taken}, {cycles, next}}
+		}
+		return []choice{{branch, taken}}
+	case OP_INVALID:
+		return []choice{{cycles, nil}}
+	}
+	return []choice{{cycles, next}}
+}
+
+func (g *CFG) function(entry Location) (*Function, bool) {
+	f, ok := g.byEntry[entry]
+	return f, ok
+}
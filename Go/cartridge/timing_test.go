@@ -0,0 +1,127 @@
+package cartridge
+
+import "testing"
+
+func Test_Timing(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0xCD, 0x60, 0x01, // call 0x160
+			0x18, 0xFB}, // jr 0x150
+		0x160: {0x3C, // inc a
+			0x20, 0x02, // jr nz, 0x165
+			0x3D,  // dec a
+			0xC8,  // ret z
+			0x3C,  // inc a
+			0xC9}, // ret
+	})
+	c, err := New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := c.CFG()
+
+	f, ok := g.Function(Location{Bank: 0, Address: 0x160})
+	if !ok {
+		t.Fatalf("Expected a function at 0x160")
+	}
+	//inc, jr taken, inc, ret or inc, jr, dec, ret z taken is 36 and falling through all of it is 44
+	if timing := g.Timing(f); timing != (Timing{Min: 36, Max: 44}) {
+		t.Errorf("Expected the function to take 36-44 cycles but found %s", timing)
+	}
+	if timing := f.Blocks[0].Timing(); timing != (Timing{Min: 12, Max: 16}) {
+		t.Errorf("Expected the first block to take 12-16 cycles but found %s", timing)
+	}
+
+	//The call includes the called function and the loop back to 0x150 is counted once
+	entry, ok := g.Function(Location{Bank: 0, Address: 0x100})
+	if !ok {
+		t.Fatalf("Expected a function at 0x100")
+	}
+	if timing := g.Subgraph(entry).Timing(entry); timing != (Timing{Min: 88, Max: 96}) {
+		t.Errorf("Expected the caller to take 88-96 cycles but found %s", timing)
+	}
+	if timing := g.Timing(entry).M(); timing != (Timing{Min: 22, Max: 24}) {
+		t.Errorf("Expected the caller to take 22-24 M-cycles but found %s", timing)
+	}
+}
+
+func Test_CyclesColumn(t *testing.T) {
+	table := []struct {
+		input []byte
+		str   string
+	}{
+		{[]byte{0x3C}, "4T 1M"},
+		{[]byte{0x20, 0x02}, "12/8T 3/2M"},
+		{[]byte{0xC4, 0x00, 0x40}, "24/12T 6/3M"},
+	}
+	for _, check := range table {
+		ins, err := Decode(check.input, 0x150)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if str := cyclesColumn(ins); str != check.str {
+			t.Errorf("Expected %X to cost %s but found %s", check.input, check.str, str)
+		}
+	}
+
+	ins, _ := Decode([]byte{0x3C}, 0x150)
+	if str := (Formatter{Address: true, Cycles: true, Align: true}).line(ins); str != "00:0150 4T 1M       inc  a" {
+		t.Errorf("Unexpected line %q", str)
+	}
+}
+
+func Test_TimingSharedLoopBlock(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0xCD, 0x60, 0x01, // call 0x160
+			0xCD, 0x80, 0x01, // call 0x180
+			0x18, 0xFE}, // jr 0x156
+		0x160: {0xCD, 0x70, 0x01, // call 0x170
+			0xC9}, // ret
+		0x170: {0x3D, // dec a
+			0xC8,             // ret z
+			0xCD, 0x60, 0x01, // call 0x160
+			0xC9}, // ret
+		//The block at 0x183 is reached straight from the entry and from the loop at 0x186
+		0x180: {0xB7, // or a
+			0x28, 0x03, // jr z, 0x186
+			0x3C,       // inc a
+			0xC8,       // ret z
+			0x00,       // nop
+			0x05,       // dec b
+			0x20, 0xFA, // jr nz, 0x183
+			0xC9}, // ret
+	})
+	c, err := New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	function := func(g *CFG, address int) *Function {
+		t.Helper()
+		f, ok := g.Function(Location{Bank: 0, Address: address})
+		if !ok {
+			t.Fatalf("Expected a function at %x", address)
+		}
+		return f
+	}
+
+	//or, jr z, inc, ret z taken is 36 and or, jr z taken, dec, jr nz taken, inc, ret z, nop,
+	//back to dec is 56, whichever way the block at 0x183 is reached first
+	g := c.CFG()
+	if timing := g.Timing(function(g, 0x180)); timing != (Timing{Min: 36, Max: 56}) {
+		t.Errorf("Expected the function to take 36-56 cycles but found %s", timing)
+	}
+
+	//The calls between 0x160 and 0x170 recurse so count as nothing, which gives the same
+	//timings whichever function is timed first
+	first := c.CFG()
+	a := first.Timing(function(first, 0x160))
+	b := first.Timing(function(first, 0x170))
+	second := c.CFG()
+	if timing := second.Timing(function(second, 0x170)); timing != b || timing != (Timing{Min: 24, Max: 52}) {
+		t.Errorf("Expected 0x170 to take 24-52 cycles on its own and after 0x160 but found %s and %s", timing, b)
+	}
+	if timing := second.Timing(function(second, 0x160)); timing != a || timing != (Timing{Min: 40, Max: 40}) {
+		t.Errorf("Expected 0x160 to take 40 cycles on its own and before 0x170 but found %s and %s", timing, a)
+	}
+}
@@ -0,0 +1,173 @@
+package cartridge
+
+import "sort"
+
+// XrefKind is how an instruction refers to a location
+type XrefKind int
+
+const (
+	XREF_JUMP XrefKind = iota
+	XREF_CALL
+	XREF_READ
+	XREF_WRITE
+)
+
+var xrefKindNames = [...]string{
+	XREF_JUMP:  "jump",
+	XREF_CALL:  "call",
+	XREF_READ:  "read",
+	XREF_WRITE: "write",
+}
+
+func (k XrefKind) String() string {
+	return xrefKindNames[k]
+}
+
+// Xref is a reference from an instruction to a location. For reads and writes of
+// memory outside of ROM the bank of Target is -1 as RAM banks aren't tracked, as it is
+// for reads and writes of switchable ROM from bank 0 where the mapped bank isn't known.
+type Xref struct {
+	Kind        XrefKind
+	Instruction Instruction
+	Target      Location
+}
+
+// xrefIndex holds every reference made by the decoded program, keyed by what is referenced
+type xrefIndex struct {
+	branches map[Location][]Xref
+	accesses map[Location][]Xref
+	//Index into the instructions of the instruction at each offset
+	byOffset map[int]int
+	//Locations which are called, used to spot tail calls
+	functions map[Location]bool
+}
+
+func buildXrefs(instructions []Instruction, targets map[int]Location) xrefIndex {
+	x := xrefIndex{
+		branches:  map[Location][]Xref{},
+		accesses:  map[Location][]Xref{},
+		byOffset:  make(map[int]int, len(instructions)),
+		functions: map[Location]bool{},
+	}
+
+	for i, ins := range instructions {
+		x.byOffset[ins.Offset] = i
+		if target, ok := targets[ins.Offset]; ok {
+			kind := XREF_JUMP
+			if ins.Opcode == OP_CALL || ins.Opcode == OP_RST {
+				kind = XREF_CALL
+				x.functions[target] = true
+			}
+			x.branches[target] = append(x.branches[target], Xref{Kind: kind, Instruction: ins, Target: target})
+		}
+		if address, kind, ok := memoryAccess(ins); ok {
+			target, ok := resolveAddress(address, ins.Bank)
+			if !ok {
+				target = Location{Bank: -1, Address: address}
+			}
+			x.accesses[target] = append(x.accesses[target], Xref{Kind: kind, Instruction: ins, Target: target})
+		}
+	}
+	return x
+}
+
+// memoryAccess returns the address an instruction reads or writes directly, such as
+// ld a, [n16], ld [n16], a and ldh. Accesses through a register pair aren't known.
+func memoryAccess(ins Instruction) (int, XrefKind, bool) {
+	for i, op := range ins.Operands {
+		if !op.Indirect {
+			continue
+		}
+		//The memory operand is written to when it is the destination
+		kind := XREF_READ
+		if i == 0 {
+			kind = XREF_WRITE
+		}
+		switch op.Kind {
+		case OPERAND_IMM16:
+			return op.Value, kind, true
+		case OPERAND_IMM8:
+			return 0xFF00 + op.Value, kind, true
+		}
+	}
+	return 0, 0, false
+}
+
+// BranchesTo returns the jumps, calls and rsts which have loc as their target, in ROM order
+func (c *Cartridge) BranchesTo(loc Location) []Xref {
+	return c.xrefs.branches[loc]
+}
+
+// Accesses returns the instructions which directly read or write loc, in ROM order. Outside
+// of ROM the bank is ignored as RAM banks aren't tracked. In switchable ROM only accesses of
+// loc's bank are returned, or of every bank when loc has a bank of -1.
+func (c *Cartridge) Accesses(loc Location) []Xref {
+	switch {
+	case loc.Address < SWITCHABLE_START:
+		return c.xrefs.accesses[Location{Bank: 0, Address: loc.Address}]
+	case loc.Address > SWITCHABLE_END:
+		return c.xrefs.accesses[Location{Bank: -1, Address: loc.Address}]
+	case loc.Bank >= 0:
+		return c.xrefs.accesses[loc]
+	}
+
+	xrefs := []Xref{}
+	for target, x := range c.xrefs.accesses {
+		if target.Address == loc.Address {
+			xrefs = append(xrefs, x...)
+		}
+	}
+	sort.Slice(xrefs, func(i, j int) bool {
+		return xrefs[i].Instruction.Offset < xrefs[j].Instruction.Offset
+	})
+	return xrefs
+}
+
+// Calls returns the calls and rsts made by the function starting at loc along with any
+// jumps to other functions, which are tail calls. Jumps within the function are followed
+// to find all of its code.
+func (c *Cartridge) Calls(function Location) []Xref {
+	start, ok := function.Offset()
+	if !ok {
+		return nil
+	}
+
+	calls := []Xref{}
+	visited := map[int]bool{}
+	queue := []int{start}
+	for len(queue) > 0 {
+		offset := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		for !visited[offset] {
+			visited[offset] = true
+			i, ok := c.xrefs.byOffset[offset]
+			if !ok {
+				break
+			}
+			ins := c.instructions[i]
+
+			if target, ok := c.targets[ins.Offset]; ok {
+				switch {
+				case ins.Opcode == OP_CALL || ins.Opcode == OP_RST:
+					calls = append(calls, Xref{Kind: XREF_CALL, Instruction: ins, Target: target})
+				case c.xrefs.functions[target] && target != function:
+					calls = append(calls, Xref{Kind: XREF_JUMP, Instruction: ins, Target: target})
+				default:
+					if next, ok := target.Offset(); ok {
+						queue = append(queue, next)
+					}
+				}
+			}
+			if !fallsThrough(ins) {
+				break
+			}
+			offset += ins.Length
+		}
+	}
+
+	sort.Slice(calls, func(i, j int) bool {
+		return calls[i].Instruction.Offset < calls[j].Instruction.Offset
+	})
+	return calls
+}
@@ -0,0 +1,79 @@
+package cartridge
+
+import "testing"
+
+func checkXrefs(t *testing.T, name string, xrefs []Xref, expected []int, kinds []XrefKind) {
+	t.Helper()
+	if len(xrefs) != len(expected) {
+		t.Errorf("%s: expected %d xrefs but found %v", name, len(expected), xrefs)
+		return
+	}
+	for i, x := range xrefs {
+		if x.Instruction.Offset != expected[i] || x.Kind != kinds[i] {
+			t.Errorf("%s: expected a %s at %x but found a %s at %x", name, kinds[i], expected[i], x.Kind, x.Instruction.Offset)
+		}
+	}
+}
+
+func Test_Xrefs(t *testing.T) {
+	rom := newTestROM(0x8000, map[int][]byte{
+		0x100: {0xC3, 0x50, 0x01}, // jp 0x150
+		0x150: {0xCD, 0x60, 0x01, // call 0x160
+			0xCD, 0x80, 0x01, // call 0x180
+			0xEA, 0x00, 0xC0, // ld [0xC000], a
+			0x18, 0xF5}, // jr 0x150
+		0x160: {0xFA, 0x00, 0xC0, // ld a, [0xC000]
+			0xF0, 0x44, // ldh a, [rLY]
+			0x28, 0x03, // jr z, 0x16A
+			0xCD, 0x70, 0x01, // call 0x170
+			0xC3, 0x80, 0x01}, // jp 0x180
+		0x170: {0xC9},             // ret
+		0x180: {0xE0, 0x40, 0xC9}, // ldh [rLCDC], a, ret
+	})
+	c, err := New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	check := func(name string, xrefs []Xref, expected []int, kinds []XrefKind) {
+		t.Helper()
+		checkXrefs(t, name, xrefs, expected, kinds)
+	}
+
+	check("branches to 0x150", c.BranchesTo(Location{Bank: 0, Address: 0x150}), []int{0x100, 0x159}, []XrefKind{XREF_JUMP, XREF_JUMP})
+	check("branches to 0x160", c.BranchesTo(Location{Bank: 0, Address: 0x160}), []int{0x150}, []XrefKind{XREF_CALL})
+	check("accesses of 0xC000", c.Accesses(Location{Bank: -1, Address: 0xC000}), []int{0x156, 0x160}, []XrefKind{XREF_WRITE, XREF_READ})
+	check("accesses of rLY", c.Accesses(Location{Bank: -1, Address: 0xFF44}), []int{0x163}, []XrefKind{XREF_READ})
+	check("accesses of rLCDC", c.Accesses(Location{Bank: 0, Address: 0xFF40}), []int{0x180}, []XrefKind{XREF_WRITE})
+
+	//The jp to another function is a tail call
+	check("calls from 0x160", c.Calls(Location{Bank: 0, Address: 0x160}), []int{0x167, 0x16A}, []XrefKind{XREF_CALL, XREF_JUMP})
+	check("calls from 0x100", c.Calls(Location{Bank: 0, Address: 0x100}), []int{0x150, 0x153}, []XrefKind{XREF_CALL, XREF_CALL})
+	if calls := c.Calls(Location{Bank: 0, Address: 0x170}); len(calls) != 0 {
+		t.Errorf("Expected no calls from a leaf function but found %v", calls)
+	}
+}
+
+// Reads of the same switchable address from different banks are kept apart
+func Test_XrefsSwitchableAccesses(t *testing.T) {
+	rom := newTestROM(0x10000, map[int][]byte{
+		0x100:  {0xC3, 0x50, 0x01},       // jp 0x150
+		0x150:  {0xFA, 0x10, 0x40},       // ld a, [0x4010]
+		0x153:  {0x18, 0xFE},             // jr 0x153
+		0x8000: {0xFA, 0x10, 0x40, 0xC9}, // ld a, [0x4010], ret
+		0xC000: {0xEA, 0x10, 0x40, 0xC9}, // ld [0x4010], a, ret
+	})
+	c, err := New(rom, WithSymbols(map[Location]string{
+		{Bank: 2, Address: 0x4000}: "BankTwo",
+		{Bank: 3, Address: 0x4000}: "BankThree",
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checkXrefs(t, "accesses of 02:4010", c.Accesses(Location{Bank: 2, Address: 0x4010}), []int{0x8000}, []XrefKind{XREF_READ})
+	checkXrefs(t, "accesses of 03:4010", c.Accesses(Location{Bank: 3, Address: 0x4010}), []int{0xC000}, []XrefKind{XREF_WRITE})
+	checkXrefs(t, "accesses of 01:4010", c.Accesses(Location{Bank: 1, Address: 0x4010}), []int{}, []XrefKind{})
+	//Bank 0 code could read any bank so it is only found without a bank
+	checkXrefs(t, "accesses of 4010", c.Accesses(Location{Bank: -1, Address: 0x4010}), []int{0x150, 0x8000, 0xC000}, []XrefKind{XREF_READ, XREF_READ, XREF_WRITE})
+}
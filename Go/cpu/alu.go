@@ -0,0 +1,106 @@
+package cpu
+
+import "github.com/grab-a-byte/gameboy/cartridge"
+
+// alu performs an 8 bit arithmetic or logic operation on a, updating the flags.
+// cp sets the flags of sub without storing the result.
+func (c *CPU) alu(op cartridge.Opcode, v byte) {
+	a := c.A
+	switch op {
+	case cartridge.OP_ADD:
+		c.A = a + v
+		c.setFlags(c.A == 0, false, a&0xF+v&0xF > 0xF, int(a)+int(v) > 0xFF)
+	case cartridge.OP_ADC:
+		carry := c.carry()
+		c.A = a + v + carry
+		c.setFlags(c.A == 0, false, a&0xF+v&0xF+carry > 0xF, int(a)+int(v)+int(carry) > 0xFF)
+	case cartridge.OP_SUB, cartridge.OP_CP:
+		r := a - v
+		c.setFlags(r == 0, true, a&0xF < v&0xF, a < v)
+		if op == cartridge.OP_SUB {
+			c.A = r
+		}
+	case cartridge.OP_SBC:
+		carry := c.carry()
+		c.A = a - v - carry
+		c.setFlags(c.A == 0, true, int(a&0xF) < int(v&0xF)+int(carry), int(a) < int(v)+int(carry))
+	case cartridge.OP_AND:
+		c.A = a & v
+		c.setFlags(c.A == 0, false, true, false)
+	case cartridge.OP_XOR:
+		c.A = a ^ v
+		c.setFlags(c.A == 0, false, false, false)
+	case cartridge.OP_OR:
+		c.A = a | v
+		c.setFlags(c.A == 0, false, false, false)
+	}
+}
+
+// shift performs the rotates and shifts of the 0xCB prefixed opcodes
+func (c *CPU) shift(op cartridge.Opcode, v byte) byte {
+	var r, out byte
+	switch op {
+	case cartridge.OP_RLC:
+		r, out = v<<1|v>>7, v>>7
+	case cartridge.OP_RRC:
+		r, out = v>>1|v<<7, v&1
+	case cartridge.OP_RL:
+		r, out = v<<1|c.carry(), v>>7
+	case cartridge.OP_RR:
+		r, out = v>>1|c.carry()<<7, v&1
+	case cartridge.OP_SLA:
+		r, out = v<<1, v>>7
+	case cartridge.OP_SRA:
+		r, out = v>>1|v&0x80, v&1
+	case cartridge.OP_SRL:
+		r, out = v>>1, v&1
+	case cartridge.OP_SWAP:
+		r = v<<4 | v>>4
+	}
+	c.setFlags(r == 0, false, false, out == 1)
+	return r
+}
+
+// daa adjusts a to binary coded decimal after an addition or subtraction
+func (c *CPU) daa() {
+	a := c.A
+	carry := c.Flag(FLAG_C)
+	if !c.Flag(FLAG_N) {
+		if c.Flag(FLAG_H) || a&0xF > 9 {
+			a += 0x06
+		}
+		if carry || c.A > 0x99 {
+			a += 0x60
+			carry = true
+		}
+	} else {
+		if c.Flag(FLAG_H) {
+			a -= 0x06
+		}
+		if carry {
+			a -= 0x60
+		}
+	}
+	c.A = a
+	c.setFlag(FLAG_Z, a == 0)
+	c.setFlag(FLAG_H, false)
+	c.setFlag(FLAG_C, carry)
+}
+
+// addSP adds a signed offset to sp for add sp, e8 and ld hl, sp + e8. The flags come
+// from adding the offset as an unsigned byte to the low byte of sp.
+func (c *CPU) addSP(offset int) uint16 {
+	sp := c.SP
+	e := uint16(byte(offset))
+	c.setFlags(false, false, sp&0xF+e&0xF > 0xF, sp&0xFF+e > 0xFF)
+	return sp + uint16(int16(offset))
+}
+
+// addHL adds a 16 bit register to hl, leaving Z alone
+func (c *CPU) addHL(v uint16) {
+	hl := c.HL()
+	c.setFlag(FLAG_N, false)
+	c.setFlag(FLAG_H, hl&0xFFF+v&0xFFF > 0xFFF)
+	c.setFlag(FLAG_C, int(hl)+int(v) > 0xFFFF)
+	c.SetHL(hl + v)
+}
@@ -0,0 +1,59 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// bcd packs a number below 100 into two decimal digits
+func bcd(n int) byte {
+	return byte(n/10<<4 | n%10)
+}
+
+func Test_DAA(t *testing.T) {
+	for x := 0; x < 100; x++ {
+		for y := 0; y < 100; y++ {
+			c := &CPU{}
+			c.A = bcd(x)
+			c.alu(cartridge.OP_ADD, bcd(y))
+			c.daa()
+			if c.A != bcd((x+y)%100) || c.Flag(FLAG_C) != (x+y >= 100) {
+				t.Fatalf("Expected %d + %d to adjust to %02X but found %02X", x, y, bcd((x+y)%100), c.A)
+			}
+
+			c.A = bcd(x)
+			c.alu(cartridge.OP_SUB, bcd(y))
+			c.daa()
+			if c.A != bcd((x-y+100)%100) || c.Flag(FLAG_C) != (x < y) {
+				t.Fatalf("Expected %d - %d to adjust to %02X but found %02X", x, y, bcd((x-y+100)%100), c.A)
+			}
+		}
+	}
+}
+
+func Test_Shifts(t *testing.T) {
+	table := []struct {
+		op     cartridge.Opcode
+		input  byte
+		carry  bool
+		output byte
+		flags  byte
+	}{
+		{cartridge.OP_RLC, 0x00, false, 0x00, FLAG_Z},
+		{cartridge.OP_RRC, 0x01, false, 0x80, FLAG_C},
+		{cartridge.OP_RL, 0x00, true, 0x01, 0},
+		{cartridge.OP_RR, 0x00, true, 0x80, 0},
+		{cartridge.OP_SLA, 0x80, false, 0x00, FLAG_Z | FLAG_C},
+		{cartridge.OP_SRA, 0x81, false, 0xC0, FLAG_C},
+		{cartridge.OP_SRL, 0x81, false, 0x40, FLAG_C},
+		{cartridge.OP_SWAP, 0x00, true, 0x00, FLAG_Z},
+	}
+	for _, check := range table {
+		c := &CPU{}
+		c.setFlag(FLAG_C, check.carry)
+		if r := c.shift(check.op, check.input); r != check.output || c.F != check.flags {
+			t.Errorf("%s %02X: expected %02X with flags %02X but found %02X with %02X", check.op, check.input, check.output, check.flags, r, c.F)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Package cpu executes SM83 instructions, decoding them with the same tables as the disassembler
+package cpu
+
+import (
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// Bus is the memory the CPU reads and writes, including the interrupt registers
+type Bus interface {
+	Read(address uint16) byte
+	Write(address uint16, value byte)
+}
+
+// Interrupt bits of IE and IF, lower bits have priority
+const (
+	INT_VBLANK byte = 1 << iota
+	INT_STAT
+	INT_TIMER
+	INT_SERIAL
+	INT_JOYPAD
+)
+
+const (
+	IF_ADDRESS = 0xFF0F
+	IE_ADDRESS = 0xFFFF
+	//Handlers are at 0x40, 0x48, 0x50, 0x58 and 0x60 in the order of the interrupt bits
+	INTERRUPT_VECTORS = 0x40
+	//T-cycles taken to push pc and jump to an interrupt handler
+	INTERRUPT_CYCLES = 20
+)
+
+// CPU is an SM83 with its registers and the state of interrupts and low power modes
+type CPU struct {
+	Registers
+	//Interrupt master enable, set by ei and reti and cleared by di and interrupts
+	IME bool
+	//Halted waits for an interrupt to be requested
+	Halted bool
+	//Stopped waits for a button press
+	Stopped bool
+	//Locked is set by an invalid opcode, after which the CPU never runs another instruction
+	Locked bool
+
+	bus Bus
+	//ei takes effect after the instruction which follows it
+	eiDelay bool
+	//halt with an interrupt already pending and IME clear fails to advance pc past the next opcode
+	haltBug bool
+}
+
+// New returns a CPU on bus with the registers left by the DMG boot ROM
+func New(bus Bus) *CPU {
+	c := &CPU{bus: bus}
+	c.Reset()
+	return c
+}
+
+// Reset sets the registers to what the DMG boot ROM leaves them as when it jumps to 0x100
+func (c *CPU) Reset() {
+	c.Registers = Registers{A: 0x01, F: 0xB0, B: 0x00, C: 0x13, D: 0x00, E: 0xD8, H: 0x01, L: 0x4D, SP: 0xFFFE, PC: 0x0100}
+	c.IME, c.Halted, c.Stopped, c.Locked = false, false, false, false
+	c.eiDelay, c.haltBug = false, false
+}
+
+// Step runs one instruction, or services an interrupt, and returns the T-cycles it took.
+// While halted, stopped or locked it returns the 4 T-cycles spent waiting.
+func (c *CPU) Step() int {
+	if c.Locked {
+		return 4
+	}
+	if c.Stopped {
+		if c.bus.Read(IF_ADDRESS)&INT_JOYPAD == 0 {
+			return 4
+		}
+		c.Stopped = false
+	}
+
+	pending := c.pending()
+	if c.Halted {
+		//An interrupt wakes the CPU even when IME is clear, it just isn't serviced
+		if pending == 0 {
+			return 4
+		}
+		c.Halted = false
+	}
+	if c.IME && pending != 0 {
+		return c.interrupt(pending)
+	}
+
+	enable := c.eiDelay
+	c.eiDelay = false
+	ins := c.fetch()
+	cycles := c.execute(ins)
+	if enable && ins.Opcode != cartridge.OP_DI {
+		c.IME = true
+	}
+	return cycles
+}
+
+// pending returns the interrupts which are both requested and enabled
+func (c *CPU) pending() byte {
+	return c.bus.Read(IF_ADDRESS) & c.bus.Read(IE_ADDRESS) & 0x1F
+}
+
+// interrupt jumps to the handler of the highest priority pending interrupt
+func (c *CPU) interrupt(pending byte) int {
+	for i := 0; i < 5; i++ {
+		bit := byte(1) << i
+		if pending&bit == 0 {
+			continue
+		}
+		c.IME = false
+		c.bus.Write(IF_ADDRESS, c.bus.Read(IF_ADDRESS)&^bit)
+		c.push(c.PC)
+		c.PC = uint16(INTERRUPT_VECTORS + i*8)
+		break
+	}
+	return INTERRUPT_CYCLES
+}
+
+// fetch decodes the instruction at pc and moves pc past it
+func (c *CPU) fetch() cartridge.Instruction {
+	pc := c.PC
+	first := c.bus.Read(pc)
+	length := cartridge.Reference(false, first).Length
+	if first == 0xCB {
+		length = 2
+	}
+
+	//The halt bug reads the opcode without incrementing pc, so it is read again as the next byte
+	next := pc + 1
+	if c.haltBug {
+		next, c.haltBug = pc, false
+	}
+	bytes := []byte{first}
+	for i := 0; i < length-1; i++ {
+		bytes = append(bytes, c.bus.Read(next+uint16(i)))
+	}
+	c.PC = next + uint16(length-1)
+
+	//Bytes always holds a whole instruction so there is no error to handle
+	ins, _ := cartridge.Decode(bytes, int(pc))
+	return ins
+}
+
+// push writes the high byte first, as the hardware does
+func (c *CPU) push(v uint16) {
+	c.SP--
+	c.bus.Write(c.SP, byte(v>>8))
+	c.SP--
+	c.bus.Write(c.SP, byte(v))
+}
+
+func (c *CPU) pop() uint16 {
+	v := c.read16(c.SP)
+	c.SP += 2
+	return v
+}
+
+func (c *CPU) read16(address uint16) uint16 {
+	return uint16(c.bus.Read(address)) | uint16(c.bus.Read(address+1))<<8
+}
+
+func (c *CPU) write16(address uint16, v uint16) {
+	c.bus.Write(address, byte(v))
+	c.bus.Write(address+1, byte(v>>8))
+}
@@ -0,0 +1,346 @@
+package cpu
+
+import (
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// testBus is 64KiB of flat memory
+type testBus [0x10000]byte
+
+func (b *testBus) Read(address uint16) byte {
+	return b[address]
+}
+
+func (b *testBus) Write(address uint16, value byte) {
+	b[address] = value
+}
+
+// newTestCPU loads program at 0x100 with pc pointing at it and everything else cleared
+func newTestCPU(program []byte) (*CPU, *testBus) {
+	bus := &testBus{}
+	copy(bus[0x100:], program)
+	c := New(bus)
+	c.Registers = Registers{SP: 0xFFFE, PC: 0x100}
+	return c, bus
+}
+
+func Test_Instructions(t *testing.T) {
+	table := []struct {
+		name    string
+		program []byte
+		before  Registers
+		after   Registers
+		cycles  int
+	}{
+		{"nop", []byte{0x00}, Registers{}, Registers{}, 4},
+		{"ld b, n8", []byte{0x06, 0x42}, Registers{}, Registers{B: 0x42}, 8},
+		{"ld bc, n16", []byte{0x01, 0x34, 0x12}, Registers{}, Registers{B: 0x12, C: 0x34}, 12},
+		{"ld a, e", []byte{0x7B}, Registers{E: 0x99}, Registers{A: 0x99, E: 0x99}, 4},
+		{"ld sp, hl", []byte{0xF9}, Registers{H: 0xC0, L: 0x10}, Registers{H: 0xC0, L: 0x10, SP: 0xC010}, 8},
+		{"ld hl, sp + e8", []byte{0xF8, 0xFF}, Registers{SP: 0x00FF}, Registers{H: 0x00, L: 0xFE, SP: 0x00FF, F: FLAG_H | FLAG_C}, 12},
+		{"add sp, e8", []byte{0xE8, 0x02}, Registers{SP: 0xFFFE}, Registers{SP: 0x0000, F: FLAG_H | FLAG_C}, 16},
+		{"add sp, -e8", []byte{0xE8, 0xFE}, Registers{SP: 0x1000}, Registers{SP: 0x0FFE}, 16},
+		{"inc b", []byte{0x04}, Registers{B: 0x0F, F: FLAG_C}, Registers{B: 0x10, F: FLAG_H | FLAG_C}, 4},
+		{"inc b to zero", []byte{0x04}, Registers{B: 0xFF}, Registers{B: 0x00, F: FLAG_Z | FLAG_H}, 4},
+		{"dec b", []byte{0x05}, Registers{B: 0x10}, Registers{B: 0x0F, F: FLAG_N | FLAG_H}, 4},
+		{"dec b to zero", []byte{0x05}, Registers{B: 0x01}, Registers{B: 0x00, F: FLAG_Z | FLAG_N}, 4},
+		{"inc bc leaves flags", []byte{0x03}, Registers{B: 0xFF, C: 0xFF, F: FLAG_Z}, Registers{F: FLAG_Z}, 8},
+		{"dec de", []byte{0x1B}, Registers{}, Registers{D: 0xFF, E: 0xFF}, 8},
+		{"add a, b", []byte{0x80}, Registers{A: 0x3A, B: 0xC6}, Registers{A: 0x00, B: 0xC6, F: FLAG_Z | FLAG_H | FLAG_C}, 4},
+		{"adc a, n8", []byte{0xCE, 0x0F}, Registers{A: 0xE1, F: FLAG_C}, Registers{A: 0xF1, F: FLAG_H}, 8},
+		{"sub a, n8", []byte{0xD6, 0x0F}, Registers{A: 0x3E}, Registers{A: 0x2F, F: FLAG_N | FLAG_H}, 8},
+		{"sub a, a", []byte{0x97}, Registers{A: 0x3E}, Registers{A: 0x00, F: FLAG_Z | FLAG_N}, 4},
+		{"sbc a, h", []byte{0x9C}, Registers{A: 0x3B, H: 0x2A, F: FLAG_C}, Registers{A: 0x10, H: 0x2A, F: FLAG_N}, 4},
+		{"sbc a, n8 borrow", []byte{0xDE, 0x4F}, Registers{A: 0x3B, F: FLAG_C}, Registers{A: 0xEB, F: FLAG_N | FLAG_H | FLAG_C}, 8},
+		{"and a, l", []byte{0xA5}, Registers{A: 0x5A, L: 0x3F}, Registers{A: 0x1A, L: 0x3F, F: FLAG_H}, 4},
+		{"xor a, a", []byte{0xAF}, Registers{A: 0xFF, F: FLAG_C}, Registers{F: FLAG_Z}, 4},
+		{"or a, n8", []byte{0xF6, 0x03}, Registers{A: 0x5A}, Registers{A: 0x5B}, 8},
+		{"cp a, n8", []byte{0xFE, 0x40}, Registers{A: 0x3C}, Registers{A: 0x3C, F: FLAG_N | FLAG_C}, 8},
+		{"cp a, equal", []byte{0xFE, 0x3C}, Registers{A: 0x3C}, Registers{A: 0x3C, F: FLAG_Z | FLAG_N}, 8},
+		{"add hl, bc", []byte{0x09}, Registers{H: 0x8A, L: 0x23, B: 0x06, C: 0x05, F: FLAG_Z}, Registers{H: 0x90, L: 0x28, B: 0x06, C: 0x05, F: FLAG_Z | FLAG_H}, 8},
+		{"add hl, hl", []byte{0x29}, Registers{H: 0x8A, L: 0x23}, Registers{H: 0x14, L: 0x46, F: FLAG_H | FLAG_C}, 8},
+		{"rlca", []byte{0x07}, Registers{A: 0x85}, Registers{A: 0x0B, F: FLAG_C}, 4},
+		{"rla", []byte{0x17}, Registers{A: 0x95, F: FLAG_C}, Registers{A: 0x2B, F: FLAG_C}, 4},
+		{"rrca", []byte{0x0F}, Registers{A: 0x3B}, Registers{A: 0x9D, F: FLAG_C}, 4},
+		{"rra clears z", []byte{0x1F}, Registers{A: 0x01}, Registers{A: 0x00, F: FLAG_C}, 4},
+		{"daa after add", []byte{0x27}, Registers{A: 0x7D}, Registers{A: 0x83}, 4},
+		{"daa after sub", []byte{0x27}, Registers{A: 0x4B, F: FLAG_N | FLAG_H}, Registers{A: 0x45, F: FLAG_N}, 4},
+		{"cpl", []byte{0x2F}, Registers{A: 0x35}, Registers{A: 0xCA, F: FLAG_N | FLAG_H}, 4},
+		{"scf", []byte{0x37}, Registers{F: FLAG_Z | FLAG_N | FLAG_H}, Registers{F: FLAG_Z | FLAG_C}, 4},
+		{"ccf", []byte{0x3F}, Registers{F: FLAG_C | FLAG_H}, Registers{}, 4},
+		{"jr", []byte{0x18, 0x05}, Registers{}, Registers{PC: 0x107}, 12},
+		{"jr backwards", []byte{0x18, 0xFE}, Registers{}, Registers{PC: 0x100}, 12},
+		{"jr nz taken", []byte{0x20, 0x05}, Registers{}, Registers{PC: 0x107}, 12},
+		{"jr nz not taken", []byte{0x20, 0x05}, Registers{F: FLAG_Z}, Registers{PC: 0x102, F: FLAG_Z}, 8},
+		{"jp a16", []byte{0xC3, 0x50, 0x01}, Registers{}, Registers{PC: 0x150}, 16},
+		{"jp c not taken", []byte{0xDA, 0x50, 0x01}, Registers{}, Registers{PC: 0x103}, 12},
+		{"jp hl", []byte{0xE9}, Registers{H: 0x12, L: 0x34}, Registers{H: 0x12, L: 0x34, PC: 0x1234}, 4},
+		{"call", []byte{0xCD, 0x00, 0x20}, Registers{SP: 0xFFFE}, Registers{SP: 0xFFFC, PC: 0x2000}, 24},
+		{"call nc not taken", []byte{0xD4, 0x00, 0x20}, Registers{SP: 0xFFFE, F: FLAG_C}, Registers{SP: 0xFFFE, PC: 0x103, F: FLAG_C}, 12},
+		{"rst", []byte{0xFF}, Registers{SP: 0xFFFE}, Registers{SP: 0xFFFC, PC: 0x38}, 16},
+		{"ret z taken", []byte{0xC8}, Registers{SP: 0x100, F: FLAG_Z}, Registers{SP: 0x102, PC: 0x00C8, F: FLAG_Z}, 20},
+		{"ret z not taken", []byte{0xC8}, Registers{SP: 0xFFFC}, Registers{SP: 0xFFFC, PC: 0x101}, 8},
+		{"push bc", []byte{0xC5}, Registers{B: 0x12, C: 0x34, SP: 0xFFFE}, Registers{B: 0x12, C: 0x34, SP: 0xFFFC}, 16},
+		{"pop de", []byte{0xD1}, Registers{SP: 0xFFFC}, Registers{SP: 0xFFFE}, 12},
+		{"rlc b", []byte{0xCB, 0x00}, Registers{B: 0x85}, Registers{B: 0x0B, F: FLAG_C}, 8},
+		{"rl a to zero", []byte{0xCB, 0x17}, Registers{A: 0x80}, Registers{A: 0x00, F: FLAG_Z | FLAG_C}, 8},
+		{"rr c", []byte{0xCB, 0x19}, Registers{C: 0x01, F: FLAG_C}, Registers{C: 0x80, F: FLAG_C}, 8},
+		{"sla d", []byte{0xCB, 0x22}, Registers{D: 0xFF}, Registers{D: 0xFE, F: FLAG_C}, 8},
+		{"sra e", []byte{0xCB, 0x2B}, Registers{E: 0x8A}, Registers{E: 0xC5}, 8},
+		{"srl h", []byte{0xCB, 0x3C}, Registers{H: 0x01}, Registers{H: 0x00, F: FLAG_Z | FLAG_C}, 8},
+		{"swap l", []byte{0xCB, 0x35}, Registers{L: 0xF0, F: FLAG_C}, Registers{L: 0x0F}, 8},
+		{"bit 7, h", []byte{0xCB, 0x7C}, Registers{H: 0x7F, F: FLAG_C}, Registers{H: 0x7F, F: FLAG_Z | FLAG_H | FLAG_C}, 8},
+		{"bit 0, a", []byte{0xCB, 0x47}, Registers{A: 0x01}, Registers{A: 0x01, F: FLAG_H}, 8},
+		{"res 0, a", []byte{0xCB, 0x87}, Registers{A: 0xFF}, Registers{A: 0xFE}, 8},
+		{"set 3, b", []byte{0xCB, 0xD8}, Registers{}, Registers{B: 0x08}, 8},
+		{"di", []byte{0xF3}, Registers{}, Registers{}, 4},
+	}
+	for _, check := range table {
+		c, _ := newTestCPU(check.program)
+		check.before.PC = 0x100
+		if check.before.SP == 0 && check.after.SP == 0 {
+			check.before.SP, check.after.SP = 0xFFFE, 0xFFFE
+		}
+		if check.after.PC == 0 {
+			check.after.PC = 0x100 + uint16(len(check.program))
+		}
+		c.Registers = check.before
+
+		cycles := c.Step()
+		if c.Registers != check.after {
+			t.Errorf("%s: expected %+v but found %+v", check.name, check.after, c.Registers)
+		}
+		if cycles != check.cycles {
+			t.Errorf("%s: expected %d cycles but found %d", check.name, check.cycles, cycles)
+		}
+	}
+}
+
+func Test_Memory(t *testing.T) {
+	c, bus := newTestCPU([]byte{
+		0x22,       // ld [hl+], a
+		0x32,       // ld [hl-], a
+		0x34,       // inc [hl]
+		0xE0, 0x80, // ldh [$FF80], a
+		0xE2,             // ldh [c], a
+		0x08, 0x00, 0xD0, // ld [$D000], sp
+		0xF5, // push af
+		0xC1, // pop bc
+		0x2A, // ld a, [hl+]
+	})
+	c.A, c.F, c.C, c.SP = 0x42, 0xB0, 0x81, 0xDFFE
+	c.SetHL(0xC000)
+
+	for i := 0; i < 9; i++ {
+		c.Step()
+	}
+	if bus[0xC000] != 0x43 || bus[0xC001] != 0x42 {
+		t.Errorf("Expected ld [hl+], ld [hl-] and inc [hl] to leave 43 42 but found %02X %02X", bus[0xC000], bus[0xC001])
+	}
+	if bus[0xFF80] != 0x42 || bus[0xFF81] != 0x42 {
+		t.Errorf("Expected ldh to write to $FF80 and $FF81")
+	}
+	if bus[0xD000] != 0xFE || bus[0xD001] != 0xDF {
+		t.Errorf("Expected sp to be stored little endian but found %02X %02X", bus[0xD000], bus[0xD001])
+	}
+	//inc [hl] cleared Z, N and H but left C alone
+	if c.BC() != 0x4210 {
+		t.Errorf("Expected push af and pop bc to copy af but found %04X", c.BC())
+	}
+	if c.A != 0x43 || c.HL() != 0xC001 {
+		t.Errorf("Expected ld a, [hl+] to load 43 and step hl but found %02X and %04X", c.A, c.HL())
+	}
+}
+
+func Test_PopAFMasksFlags(t *testing.T) {
+	c, bus := newTestCPU([]byte{0xF1}) // pop af
+	c.SP = 0xC000
+	bus[0xC000], bus[0xC001] = 0xFF, 0x12
+	c.Step()
+	if c.A != 0x12 || c.F != 0xF0 {
+		t.Errorf("Expected af to be 12F0 but found %04X", c.AF())
+	}
+}
+
+func Test_Interrupts(t *testing.T) {
+	c, bus := newTestCPU([]byte{0x00})
+	c.IME = true
+	bus[IE_ADDRESS] = INT_TIMER | INT_VBLANK
+	bus[IF_ADDRESS] = INT_TIMER | INT_VBLANK | INT_SERIAL
+
+	if cycles := c.Step(); cycles != INTERRUPT_CYCLES {
+		t.Errorf("Expected the interrupt to take %d cycles but found %d", INTERRUPT_CYCLES, cycles)
+	}
+	if c.PC != 0x40 || c.IME {
+		t.Errorf("Expected vblank to be serviced first with IME cleared but pc is %04X", c.PC)
+	}
+	if bus[IF_ADDRESS] != INT_TIMER|INT_SERIAL {
+		t.Errorf("Expected only the vblank request to be cleared but IF is %02X", bus[IF_ADDRESS])
+	}
+	if c.SP != 0xFFFC || c.read16(c.SP) != 0x100 {
+		t.Errorf("Expected the interrupted pc to be pushed")
+	}
+
+	//reti returns and enables interrupts straight away so the timer is serviced next
+	bus[0x40] = 0xD9
+	c.Step()
+	if c.PC != 0x100 || !c.IME {
+		t.Errorf("Expected reti to return to 0x100 with IME set but pc is %04X", c.PC)
+	}
+	c.Step()
+	if c.PC != 0x50 {
+		t.Errorf("Expected the timer interrupt to be serviced but pc is %04X", c.PC)
+	}
+}
+
+func Test_EIDelay(t *testing.T) {
+	c, bus := newTestCPU([]byte{
+		0xFB, // ei
+		0x00, // nop
+		0x00, // nop
+	})
+	bus[IE_ADDRESS] = INT_JOYPAD
+	bus[IF_ADDRESS] = INT_JOYPAD
+
+	c.Step()
+	if c.IME {
+		t.Errorf("Expected ei not to take effect until after the next instruction")
+	}
+	c.Step()
+	if c.PC != 0x102 || !c.IME {
+		t.Errorf("Expected the nop after ei to run before interrupts are enabled, pc is %04X", c.PC)
+	}
+	c.Step()
+	if c.PC != 0x60 {
+		t.Errorf("Expected the joypad interrupt to be serviced but pc is %04X", c.PC)
+	}
+
+	//di straight after ei cancels it
+	c, _ = newTestCPU([]byte{0xFB, 0xF3, 0x00})
+	c.Step()
+	c.Step()
+	c.Step()
+	if c.IME {
+		t.Errorf("Expected ei followed by di to leave interrupts disabled")
+	}
+}
+
+func Test_Halt(t *testing.T) {
+	c, bus := newTestCPU([]byte{
+		0x76, // halt
+		0x3C, // inc a
+	})
+	bus[IE_ADDRESS] = INT_STAT
+
+	c.Step()
+	for i := 0; i < 3; i++ {
+		if cycles := c.Step(); cycles != 4 || !c.Halted {
+			t.Fatalf("Expected the CPU to stay halted")
+		}
+	}
+
+	//With IME clear the interrupt wakes the CPU without being serviced
+	bus[IF_ADDRESS] = INT_STAT
+	c.Step()
+	if c.Halted || c.PC != 0x102 || c.A != 1 {
+		t.Errorf("Expected halt to end and inc a to run but pc is %04X and a is %02X", c.PC, c.A)
+	}
+	if bus[IF_ADDRESS] != INT_STAT {
+		t.Errorf("Expected the interrupt to stay requested")
+	}
+}
+
+func Test_HaltBug(t *testing.T) {
+	c, bus := newTestCPU([]byte{
+		0x76,       // halt
+		0x06, 0x04, // ld b, $04
+	})
+	bus[IE_ADDRESS] = INT_VBLANK
+	bus[IF_ADDRESS] = INT_VBLANK
+
+	//halt doesn't halt, and the opcode after it is read twice so ld b, $04 becomes
+	//ld b, $06 followed by inc b
+	c.Step()
+	if c.Halted {
+		t.Errorf("Expected halt with an interrupt pending and IME clear not to halt")
+	}
+	c.Step()
+	if c.B != 0x06 || c.PC != 0x102 {
+		t.Errorf("Expected ld b, $06 with pc at 0x102 but found b %02X and pc %04X", c.B, c.PC)
+	}
+	c.Step()
+	if c.B != 0x07 || c.PC != 0x103 {
+		t.Errorf("Expected inc b with pc at 0x103 but found b %02X and pc %04X", c.B, c.PC)
+	}
+}
+
+func Test_Stop(t *testing.T) {
+	c, bus := newTestCPU([]byte{
+		0x10, 0x00, // stop
+		0x3C, // inc a
+	})
+	c.Step()
+	if !c.Stopped || c.PC != 0x102 {
+		t.Fatalf("Expected stop to stop the CPU after its padding byte")
+	}
+	c.Step()
+	if c.A != 0 {
+		t.Errorf("Expected nothing to run while stopped")
+	}
+
+	bus[IF_ADDRESS] = INT_JOYPAD
+	c.Step()
+	if c.Stopped || c.A != 1 {
+		t.Errorf("Expected a button press to resume the CPU")
+	}
+}
+
+func Test_InvalidOpcodeLocks(t *testing.T) {
+	c, _ := newTestCPU([]byte{0xD3, 0x3C})
+	c.Step()
+	for i := 0; i < 3; i++ {
+		c.Step()
+	}
+	if !c.Locked || c.A != 0 || c.PC != 0x101 {
+		t.Errorf("Expected the CPU to lock on an invalid opcode")
+	}
+}
+
+// Test_FlagsMatchReference runs every opcode from both flag states and checks that the
+// flags the reference table fixes or leaves alone come out that way
+func Test_FlagsMatchReference(t *testing.T) {
+	for prefix := 0; prefix < 2; prefix++ {
+		for code := 0; code < 0x100; code++ {
+			info := cartridge.Reference(prefix == 1, byte(code))
+			if !info.Valid() || info.Mnemonic == "prefix" {
+				continue
+			}
+			program := []byte{byte(code), 0x00, 0x00}
+			if prefix == 1 {
+				program = []byte{0xCB, byte(code)}
+			}
+			for _, f := range []byte{0x00, 0xF0} {
+				c, _ := newTestCPU(program)
+				c.F = f
+				c.Step()
+				for i, flag := range []byte{FLAG_Z, FLAG_N, FLAG_H, FLAG_C} {
+					expected := f&flag != 0
+					switch info.Flags[i] {
+					case '0':
+						expected = false
+					case '1':
+						expected = true
+					case '-':
+					default:
+						continue
+					}
+					if c.Flag(flag) != expected {
+						t.Errorf("%s: expected flag %d to be %t starting from F %02X", info.Mnemonic, i, expected, f)
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,265 @@
+package cpu
+
+import (
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// execute runs a decoded instruction, with pc already past it, and returns its T-cycles
+func (c *CPU) execute(ins cartridge.Instruction) int {
+	ops := ins.Operands
+	switch ins.Opcode {
+	case cartridge.OP_NOP:
+	case cartridge.OP_LD:
+		c.load(ops[0], ops[1])
+	case cartridge.OP_LDH:
+		c.write8(ops[0], c.read8(ops[1]))
+	case cartridge.OP_INC, cartridge.OP_DEC:
+		delta := 1
+		if ins.Opcode == cartridge.OP_DEC {
+			delta = -1
+		}
+		if is16(ops[0]) {
+			c.set16(ops[0].Register, c.get16(ops[0].Register)+uint16(delta))
+			break
+		}
+		v := c.read8(ops[0])
+		r := v + byte(delta)
+		if delta > 0 {
+			c.setFlag(FLAG_H, v&0xF == 0xF)
+		} else {
+			c.setFlag(FLAG_H, v&0xF == 0)
+		}
+		c.setFlag(FLAG_Z, r == 0)
+		c.setFlag(FLAG_N, delta < 0)
+		c.write8(ops[0], r)
+	case cartridge.OP_ADD:
+		switch {
+		case ops[0].Register == cartridge.REG_HL && is16(ops[0]):
+			c.addHL(c.get16(ops[1].Register))
+		case ops[0].Register == cartridge.REG_SP:
+			c.SP = c.addSP(ops[1].Value)
+		default:
+			c.alu(ins.Opcode, c.read8(ops[1]))
+		}
+	case cartridge.OP_ADC, cartridge.OP_SUB, cartridge.OP_SBC, cartridge.OP_AND, cartridge.OP_XOR, cartridge.OP_OR, cartridge.OP_CP:
+		c.alu(ins.Opcode, c.read8(ops[len(ops)-1]))
+	case cartridge.OP_RLCA, cartridge.OP_RRCA, cartridge.OP_RLA, cartridge.OP_RRA:
+		//The accumulator rotates always clear Z unlike their prefixed forms
+		c.A = c.shift(accumulatorShifts[ins.Opcode], c.A)
+		c.setFlag(FLAG_Z, false)
+	case cartridge.OP_DAA:
+		c.daa()
+	case cartridge.OP_CPL:
+		c.A = ^c.A
+		c.setFlag(FLAG_N, true)
+		c.setFlag(FLAG_H, true)
+	case cartridge.OP_SCF, cartridge.OP_CCF:
+		c.setFlag(FLAG_N, false)
+		c.setFlag(FLAG_H, false)
+		c.setFlag(FLAG_C, ins.Opcode == cartridge.OP_SCF || !c.Flag(FLAG_C))
+	case cartridge.OP_JR, cartridge.OP_JP, cartridge.OP_CALL, cartridge.OP_RET:
+		if len(ops) > 0 && ops[0].Kind == cartridge.OPERAND_COND {
+			if !c.condition(ops[0].Cond) {
+				return ins.Cycles
+			}
+			c.branch(ins.Opcode, ops[1:])
+			return ins.BranchCycles
+		}
+		c.branch(ins.Opcode, ops)
+	case cartridge.OP_RETI:
+		c.PC = c.pop()
+		c.IME = true
+	case cartridge.OP_RST:
+		c.push(c.PC)
+		c.PC = uint16(ops[0].Value)
+	case cartridge.OP_STOP:
+		c.Stopped = true
+	case cartridge.OP_HALT:
+		if !c.IME && c.pending() != 0 {
+			c.haltBug = true
+		} else {
+			c.Halted = true
+		}
+	case cartridge.OP_DI:
+		c.IME = false
+	case cartridge.OP_EI:
+		c.eiDelay = true
+	case cartridge.OP_POP:
+		c.set16(ops[0].Register, c.pop())
+	case cartridge.OP_PUSH:
+		c.push(c.get16(ops[0].Register))
+	case cartridge.OP_RLC, cartridge.OP_RRC, cartridge.OP_RL, cartridge.OP_RR, cartridge.OP_SLA, cartridge.OP_SRA, cartridge.OP_SWAP, cartridge.OP_SRL:
+		c.write8(ops[0], c.shift(ins.Opcode, c.read8(ops[0])))
+	case cartridge.OP_BIT:
+		c.setFlag(FLAG_Z, c.read8(ops[1])&(1<<ops[0].Value) == 0)
+		c.setFlag(FLAG_N, false)
+		c.setFlag(FLAG_H, true)
+	case cartridge.OP_RES:
+		c.write8(ops[1], c.read8(ops[1])&^(1<<ops[0].Value))
+	case cartridge.OP_SET:
+		c.write8(ops[1], c.read8(ops[1])|1<<ops[0].Value)
+	case cartridge.OP_INVALID:
+		c.Locked = true
+	}
+	return ins.Cycles
+}
+
+// accumulatorShifts maps the unprefixed rotates of a onto the prefixed rotate they match
+var accumulatorShifts = map[cartridge.Opcode]cartridge.Opcode{
+	cartridge.OP_RLCA: cartridge.OP_RLC,
+	cartridge.OP_RRCA: cartridge.OP_RRC,
+	cartridge.OP_RLA:  cartridge.OP_RL,
+	cartridge.OP_RRA:  cartridge.OP_RR,
+}
+
+// load runs the many forms of ld
+func (c *CPU) load(dst, src cartridge.Operand) {
+	switch {
+	case src.Kind == cartridge.OPERAND_SP_OFFSET:
+		c.SetHL(c.addSP(src.Value))
+	case is16(dst) && src.Kind == cartridge.OPERAND_IMM16:
+		c.set16(dst.Register, uint16(src.Value))
+	case is16(dst):
+		//ld sp, hl
+		c.set16(dst.Register, c.get16(src.Register))
+	case is16(src):
+		//ld [a16], sp
+		c.write16(uint16(dst.Value), c.get16(src.Register))
+	default:
+		c.write8(dst, c.read8(src))
+	}
+}
+
+// branch jumps, calls or returns once any condition has passed
+func (c *CPU) branch(op cartridge.Opcode, ops []cartridge.Operand) {
+	switch op {
+	case cartridge.OP_JR:
+		c.PC += uint16(int16(ops[0].Value))
+	case cartridge.OP_JP:
+		if ops[0].Kind == cartridge.OPERAND_REG {
+			c.PC = c.HL()
+		} else {
+			c.PC = uint16(ops[0].Value)
+		}
+	case cartridge.OP_CALL:
+		c.push(c.PC)
+		c.PC = uint16(ops[0].Value)
+	case cartridge.OP_RET:
+		c.PC = c.pop()
+	}
+}
+
+func (c *CPU) condition(cond cartridge.Condition) bool {
+	switch cond {
+	case cartridge.COND_NZ:
+		return !c.Flag(FLAG_Z)
+	case cartridge.COND_Z:
+		return c.Flag(FLAG_Z)
+	case cartridge.COND_NC:
+		return !c.Flag(FLAG_C)
+	}
+	return c.Flag(FLAG_C)
+}
+
+// is16 reports whether an operand is one of the 16 bit register pairs
+func is16(o cartridge.Operand) bool {
+	if o.Kind != cartridge.OPERAND_REG || o.Indirect {
+		return false
+	}
+	switch o.Register {
+	case cartridge.REG_BC, cartridge.REG_DE, cartridge.REG_HL, cartridge.REG_SP, cartridge.REG_AF:
+		return true
+	}
+	return false
+}
+
+func (c *CPU) get16(r cartridge.Register) uint16 {
+	switch r {
+	case cartridge.REG_BC:
+		return c.BC()
+	case cartridge.REG_DE:
+		return c.DE()
+	case cartridge.REG_HL:
+		return c.HL()
+	case cartridge.REG_SP:
+		return c.SP
+	}
+	return c.AF()
+}
+
+func (c *CPU) set16(r cartridge.Register, v uint16) {
+	switch r {
+	case cartridge.REG_BC:
+		c.SetBC(v)
+	case cartridge.REG_DE:
+		c.SetDE(v)
+	case cartridge.REG_HL:
+		c.SetHL(v)
+	case cartridge.REG_SP:
+		c.SP = v
+	default:
+		c.SetAF(v)
+	}
+}
+
+// register8 returns the 8 bit register an operand names
+func (c *CPU) register8(r cartridge.Register) *byte {
+	switch r {
+	case cartridge.REG_B:
+		return &c.B
+	case cartridge.REG_C:
+		return &c.C
+	case cartridge.REG_D:
+		return &c.D
+	case cartridge.REG_E:
+		return &c.E
+	case cartridge.REG_H:
+		return &c.H
+	case cartridge.REG_L:
+		return &c.L
+	}
+	return &c.A
+}
+
+// address works out the memory an indirect operand refers to, stepping hl for [hl+] and [hl-]
+func (c *CPU) address(o cartridge.Operand) uint16 {
+	switch o.Kind {
+	case cartridge.OPERAND_IMM8:
+		return 0xFF00 + uint16(o.Value)
+	case cartridge.OPERAND_IMM16:
+		return uint16(o.Value)
+	}
+	switch o.Register {
+	case cartridge.REG_C:
+		return 0xFF00 + uint16(c.C)
+	case cartridge.REG_HLI:
+		hl := c.HL()
+		c.SetHL(hl + 1)
+		return hl
+	case cartridge.REG_HLD:
+		hl := c.HL()
+		c.SetHL(hl - 1)
+		return hl
+	}
+	return c.get16(o.Register)
+}
+
+// read8 reads an 8 bit register, immediate or memory operand
+func (c *CPU) read8(o cartridge.Operand) byte {
+	switch {
+	case o.Indirect:
+		return c.bus.Read(c.address(o))
+	case o.Kind == cartridge.OPERAND_REG:
+		return *c.register8(o.Register)
+	}
+	return byte(o.Value)
+}
+
+// write8 writes an 8 bit register or memory operand
+func (c *CPU) write8(o cartridge.Operand, v byte) {
+	if o.Indirect {
+		c.bus.Write(c.address(o), v)
+		return
+	}
+	*c.register8(o.Register) = v
+}
@@ -0,0 +1,82 @@
+package cpu
+
+// Bits of the F register, the low 4 bits always read as 0
+const (
+	FLAG_Z byte = 0x80
+	FLAG_N byte = 0x40
+	FLAG_H byte = 0x20
+	FLAG_C byte = 0x10
+)
+
+// Registers is the register file of the SM83
+type Registers struct {
+	A, F byte
+	B, C byte
+	D, E byte
+	H, L byte
+	SP   uint16
+	PC   uint16
+}
+
+func (r *Registers) AF() uint16 {
+	return uint16(r.A)<<8 | uint16(r.F)
+}
+
+func (r *Registers) BC() uint16 {
+	return uint16(r.B)<<8 | uint16(r.C)
+}
+
+func (r *Registers) DE() uint16 {
+	return uint16(r.D)<<8 | uint16(r.E)
+}
+
+func (r *Registers) HL() uint16 {
+	return uint16(r.H)<<8 | uint16(r.L)
+}
+
+// SetAF sets A and F, dropping the bits of F which don't exist
+func (r *Registers) SetAF(v uint16) {
+	r.A, r.F = byte(v>>8), byte(v)&0xF0
+}
+
+func (r *Registers) SetBC(v uint16) {
+	r.B, r.C = byte(v>>8), byte(v)
+}
+
+func (r *Registers) SetDE(v uint16) {
+	r.D, r.E = byte(v>>8), byte(v)
+}
+
+func (r *Registers) SetHL(v uint16) {
+	r.H, r.L = byte(v>>8), byte(v)
+}
+
+// Flag reports whether a bit of F is set
+func (r *Registers) Flag(flag byte) bool {
+	return r.F&flag != 0
+}
+
+func (r *Registers) setFlag(flag byte, set bool) {
+	if set {
+		r.F |= flag
+	} else {
+		r.F &^= flag
+	}
+}
+
+// setFlags sets all four flags at once
+func (r *Registers) setFlags(z, n, h, c bool) {
+	r.F = 0
+	r.setFlag(FLAG_Z, z)
+	r.setFlag(FLAG_N, n)
+	r.setFlag(FLAG_H, h)
+	r.setFlag(FLAG_C, c)
+}
+
+// carry is the carry flag as a number to add
+func (r *Registers) carry() byte {
+	if r.Flag(FLAG_C) {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,154 @@
+package emulator
+
+import (
+	"github.com/grab-a-byte/gameboy/cpu"
+	"github.com/grab-a-byte/gameboy/mbc"
+)
+
+// Serial registers, transfers complete straight away as nothing is connected
+const (
+	SB_ADDRESS = 0xFF01
+	SC_ADDRESS = 0xFF02
+)
+
+// bus maps the address space onto the cartridge, memory and hardware registers, see
+// https://gbdev.io/pandocs/Memory_Map.html
+type bus struct {
+	mapper mbc.Mapper
+	wram   [0x2000]byte
+	hram   [0x7F]byte
+	//Registers without their own hardware, such as sound, read back what was written
+	io [0x80]byte
+
+	ie, iflag byte
+	sb, sc    byte
+	serial    []byte
+
+	ppu    *ppu
+	timer  *timer
+	joypad *joypad
+}
+
+// newBus returns a bus with the hardware in the state the boot ROM leaves it in,
+// mapper may be nil when no ROM is loaded
+func newBus(mapper mbc.Mapper) *bus {
+	return &bus{
+		mapper: mapper,
+		iflag:  cpu.INT_VBLANK,
+		ppu:    newPPU(),
+		timer:  &timer{counter: 0xABCC},
+		joypad: &joypad{selected: JOYP_BUTTONS | JOYP_DIRECTIONS},
+	}
+}
+
+// step runs the hardware alongside the CPU for a number of T-cycles and collects the
+// interrupts it requests
+func (b *bus) step(cycles int) {
+	b.timer.step(cycles)
+	b.ppu.step(cycles)
+	b.iflag |= b.timer.requested | b.ppu.requested
+	b.timer.requested, b.ppu.requested = 0, 0
+}
+
+func (b *bus) Read(address uint16) byte {
+	switch {
+	case address < 0x8000, address >= mbc.EXTERNAL_RAM_START && address <= mbc.EXTERNAL_RAM_END:
+		if b.mapper == nil {
+			return 0xFF
+		}
+		return b.mapper.Read(address)
+	case address < 0xA000:
+		return b.ppu.vram[address-0x8000]
+	case address < 0xE000:
+		return b.wram[address-0xC000]
+	case address < 0xFE00:
+		//Echo RAM mirrors work RAM
+		return b.wram[address-0xE000]
+	case address < 0xFEA0:
+		return b.ppu.oam[address-0xFE00]
+	case address < 0xFF00:
+		return 0xFF
+	case address < 0xFF80:
+		return b.readIO(address)
+	case address < cpu.IE_ADDRESS:
+		return b.hram[address-0xFF80]
+	}
+	return b.ie
+}
+
+func (b *bus) Write(address uint16, value byte) {
+	switch {
+	case address < 0x8000, address >= mbc.EXTERNAL_RAM_START && address <= mbc.EXTERNAL_RAM_END:
+		if b.mapper != nil {
+			b.mapper.Write(address, value)
+		}
+	case address < 0xA000:
+		b.ppu.vram[address-0x8000] = value
+	case address < 0xE000:
+		b.wram[address-0xC000] = value
+	case address < 0xFE00:
+		b.wram[address-0xE000] = value
+	case address < 0xFEA0:
+		b.ppu.oam[address-0xFE00] = value
+	case address < 0xFF00:
+	case address < 0xFF80:
+		b.writeIO(address, value)
+	case address < cpu.IE_ADDRESS:
+		b.hram[address-0xFF80] = value
+	default:
+		b.ie = value
+	}
+}
+
+func (b *bus) readIO(address uint16) byte {
+	switch {
+	case address == JOYP_ADDRESS:
+		return b.joypad.read()
+	case address == SB_ADDRESS:
+		return b.sb
+	case address == SC_ADDRESS:
+		return 0x7E | b.sc
+	case address >= DIV_ADDRESS && address <= TAC_ADDRESS:
+		return b.timer.read(address)
+	case address == cpu.IF_ADDRESS:
+		return 0xE0 | b.iflag
+	case address >= LCDC_ADDRESS && address <= WX_ADDRESS && address != DMA_ADDRESS:
+		return b.ppu.read(address)
+	}
+	return b.io[address-0xFF00]
+}
+
+func (b *bus) writeIO(address uint16, value byte) {
+	switch {
+	case address == JOYP_ADDRESS:
+		b.joypad.write(value)
+	case address == SB_ADDRESS:
+		b.sb = value
+	case address == SC_ADDRESS:
+		b.sc = value & 0x81
+		//A transfer on the internal clock completes at once, receiving 0xFF from nobody
+		if b.sc == 0x81 {
+			b.serial = append(b.serial, b.sb)
+			b.sb = 0xFF
+			b.sc &^= 0x80
+			b.iflag |= cpu.INT_SERIAL
+		}
+	case address >= DIV_ADDRESS && address <= TAC_ADDRESS:
+		b.timer.write(address, value)
+		b.iflag |= b.timer.requested
+		b.timer.requested = 0
+	case address == cpu.IF_ADDRESS:
+		b.iflag = value & 0x1F
+	case address == DMA_ADDRESS:
+		//OAM DMA copies all 160 bytes at once rather than over 640 T-cycles
+		b.io[address-0xFF00] = value
+		source := uint16(value) << 8
+		for i := range b.ppu.oam {
+			b.ppu.oam[i] = b.Read(source + uint16(i))
+		}
+	case address >= LCDC_ADDRESS && address <= WX_ADDRESS:
+		b.ppu.write(address, value)
+	default:
+		b.io[address-0xFF00] = value
+	}
+}
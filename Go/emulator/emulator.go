@@ -0,0 +1,109 @@
+// Package emulator runs a DMG Game Boy headless, wiring the CPU to a memory bus with the
+// cartridge mapper, timer, joypad and PPU so ROMs can be driven from Go code and tests
+package emulator
+
+import (
+	"image"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+	"github.com/grab-a-byte/gameboy/cpu"
+	"github.com/grab-a-byte/gameboy/mbc"
+)
+
+// Emulator is a Game Boy which starts from the state the boot ROM leaves it in
+type Emulator struct {
+	cart *cartridge.Cartridge
+	bus  *bus
+	cpu  *cpu.CPU
+	//Buttons stay held across LoadROM
+	buttons Button
+}
+
+// New returns an emulator with no cartridge inserted, where the ROM area reads as 0xFF
+func New() *Emulator {
+	e := &Emulator{}
+	e.reset(nil)
+	return e
+}
+
+// LoadROM inserts a cartridge and resets the console. The options configure the mapper,
+// such as the clock an MBC3 timer reads.
+func (e *Emulator) LoadROM(rom []byte, opts ...mbc.Option) error {
+	cart, err := cartridge.New(rom)
+	if err != nil {
+		return err
+	}
+	mapper, err := mbc.New(rom, opts...)
+	if err != nil {
+		return err
+	}
+	e.cart = cart
+	e.reset(mapper)
+	return nil
+}
+
+func (e *Emulator) reset(mapper mbc.Mapper) {
+	e.bus = newBus(mapper)
+	e.bus.joypad.pressed = e.buttons
+	e.cpu = cpu.New(e.bus)
+}
+
+// StepInstruction runs one instruction, or services an interrupt, and returns the T-cycles it took
+func (e *Emulator) StepInstruction() int {
+	cycles := e.cpu.Step()
+	e.bus.step(cycles)
+	return cycles
+}
+
+// RunFrame runs until the PPU enters the vertical blank with a new frame drawn. With the
+// LCD off it runs for as long as a frame would take instead.
+func (e *Emulator) RunFrame() {
+	e.bus.ppu.frameDone = false
+	for cycles := 0; !e.bus.ppu.frameDone && cycles < CYCLES_PER_FRAME; {
+		cycles += e.StepInstruction()
+	}
+}
+
+// Framebuffer is the screen as of the last line drawn, using the colours of Palette. It is
+// drawn into as the emulator runs so copy it to keep a frame.
+func (e *Emulator) Framebuffer() *image.Paletted {
+	return e.bus.ppu.frame
+}
+
+// SetButtons sets which buttons are held, pressing one requests the joypad interrupt
+func (e *Emulator) SetButtons(mask Button) {
+	e.buttons = mask
+	e.bus.joypad.set(mask)
+	e.bus.iflag |= e.bus.joypad.requested
+	e.bus.joypad.requested = 0
+}
+
+// Read reads memory as the CPU would
+func (e *Emulator) Read(address uint16) byte {
+	return e.bus.Read(address)
+}
+
+// Write writes memory as the CPU would, including the side effects on hardware registers
+func (e *Emulator) Write(address uint16, value byte) {
+	e.bus.Write(address, value)
+}
+
+// CPU is the processor, for inspecting or setting its registers
+func (e *Emulator) CPU() *cpu.CPU {
+	return e.cpu
+}
+
+// Cartridge is the loaded cartridge, nil before LoadROM
+func (e *Emulator) Cartridge() *cartridge.Cartridge {
+	return e.cart
+}
+
+// Mapper is the loaded cartridge's mapper, which can be passed to mbc.OpenBattery
+func (e *Emulator) Mapper() mbc.Mapper {
+	return e.bus.mapper
+}
+
+// Serial is every byte sent out of the serial port, which test ROMs use to report results
+func (e *Emulator) Serial() []byte {
+	return e.bus.serial
+}
@@ -0,0 +1,194 @@
+package emulator
+
+import (
+	"os"
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/assembler"
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// testROM assembles src after an entry point which jumps to Main, using the logo from the
+// example ROM so the cartridge is accepted
+func testROM(t *testing.T, src string) []byte {
+	t.Helper()
+	example, err := os.ReadFile("../example/example.gb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	output, err := assembler.Assemble("SECTION \"entry\", ROM0[$0100]\n\tnop\n\tjp Main\n" +
+		"SECTION \"main\", ROM0[$0150]\n" + src)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rom := make([]byte, 2*cartridge.BANK_SIZE)
+	copy(rom, output)
+	copy(rom[cartridge.NINTENDO_LOGO_START:cartridge.NINTENDO_LOGO_END+1], example[cartridge.NINTENDO_LOGO_START:])
+	return rom
+}
+
+func newTestEmulator(t *testing.T, src string) *Emulator {
+	t.Helper()
+	e := New()
+	if err := e.LoadROM(testROM(t, src)); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+func Test_StepInstruction(t *testing.T) {
+	e := newTestEmulator(t, `
+Main:
+	ld a, $42
+	ld [$C000], a
+	ld [$E001], a
+`)
+	cycles := 0
+	for i := 0; i < 5; i++ {
+		cycles += e.StepInstruction()
+	}
+	if cycles != 4+16+8+16+16 {
+		t.Errorf("Expected 60 T-cycles but found %d", cycles)
+	}
+	if e.Read(0xC000) != 0x42 || e.Read(0xC001) != 0x42 {
+		t.Errorf("Expected 42 in work RAM and its echo but found %02X and %02X", e.Read(0xC000), e.Read(0xC001))
+	}
+	if e.CPU().PC != 0x0158 {
+		t.Errorf("Expected pc to be 0158 but found %04X", e.CPU().PC)
+	}
+	if e.Cartridge() == nil || e.Cartridge().Header.CartridgeType.Mapper != cartridge.MAPPER_ROM_ONLY {
+		t.Errorf("Expected the cartridge to be parsed")
+	}
+}
+
+func Test_LoadROMRejectsBadLogo(t *testing.T) {
+	if err := New().LoadROM(make([]byte, 2*cartridge.BANK_SIZE)); err == nil {
+		t.Errorf("Expected a ROM without the logo to be rejected")
+	}
+}
+
+func Test_NoCartridge(t *testing.T) {
+	e := New()
+	if e.Read(0x0100) != 0xFF {
+		t.Errorf("Expected the ROM area to read FF without a cartridge but found %02X", e.Read(0x0100))
+	}
+	e.RunFrame()
+}
+
+func Test_RunFrame(t *testing.T) {
+	//Every tile of the map is tile 0, which is filled with colour 3
+	e := newTestEmulator(t, `
+Main:
+	ld hl, $8000
+	ld b, 16
+	ld a, $FF
+.fill:
+	ld [hl+], a
+	dec b
+	jr nz, .fill
+.wait:
+	jr .wait
+`)
+	e.RunFrame()
+	if ly := e.Read(LY_ADDRESS); ly != SCREEN_HEIGHT {
+		t.Errorf("Expected the frame to end at the start of the vertical blank but LY is %d", ly)
+	}
+	e.RunFrame()
+
+	frame := e.Framebuffer()
+	if frame.Bounds().Dx() != SCREEN_WIDTH || frame.Bounds().Dy() != SCREEN_HEIGHT {
+		t.Fatalf("Unexpected framebuffer size %v", frame.Bounds())
+	}
+	for i, pixel := range frame.Pix {
+		if pixel != 3 {
+			t.Fatalf("Expected every pixel to be black but %d, %d is %d", i%frame.Stride, i/frame.Stride, pixel)
+		}
+	}
+}
+
+func Test_SetButtons(t *testing.T) {
+	e := newTestEmulator(t, `
+Main:
+	ld a, $10
+	ldh [rJOYP], a
+.read:
+	ldh a, [rJOYP]
+	ld [$C000], a
+	jr .read
+`)
+	e.SetButtons(BUTTON_A | BUTTON_UP)
+	e.RunFrame()
+	if joyp := e.Read(0xC000); joyp != 0xDE {
+		t.Errorf("Expected only A to read as pressed but found %02X", joyp)
+	}
+	if e.Read(0xFF0F)&0x10 == 0 {
+		t.Errorf("Expected pressing a button to request the joypad interrupt")
+	}
+
+	e.SetButtons(0)
+	e.RunFrame()
+	if joyp := e.Read(0xC000); joyp != 0xDF {
+		t.Errorf("Expected nothing to read as pressed but found %02X", joyp)
+	}
+}
+
+func Test_VBlankInterrupt(t *testing.T) {
+	e := newTestEmulator(t, `
+Main:
+	xor a
+	ldh [rIF], a
+	inc a
+	ldh [rIE], a
+	ei
+.wait:
+	halt
+	jr .wait
+
+SECTION "vblank", ROM0[$0040]
+	ld hl, $C000
+	inc [hl]
+	reti
+`)
+	for i := 0; i < 3; i++ {
+		e.RunFrame()
+	}
+	//Each frame ends as the interrupt is requested, so it is handled in the next
+	if count := e.Read(0xC000); count != 2 {
+		t.Errorf("Expected the handler to run twice but found %d", count)
+	}
+}
+
+func Test_Serial(t *testing.T) {
+	e := newTestEmulator(t, `
+Main:
+	ld hl, Message
+.send:
+	ld a, [hl+]
+	and a
+	jr z, .done
+	ldh [rSB], a
+	ld a, $81
+	ldh [rSC], a
+	jr .send
+.done:
+	jr .done
+Message:
+	db "Passed", 0
+`)
+	e.RunFrame()
+	if serial := string(e.Serial()); serial != "Passed" {
+		t.Errorf("Expected Passed to be sent but found %q", serial)
+	}
+}
+
+func Test_DMA(t *testing.T) {
+	e := New()
+	for i := uint16(0); i < 0xA0; i++ {
+		e.Write(0xC100+i, byte(i))
+	}
+	e.Write(DMA_ADDRESS, 0xC1)
+	if e.Read(0xFE00) != 0x00 || e.Read(0xFE9F) != 0x9F {
+		t.Errorf("Expected OAM to be copied from C100 but found %02X and %02X", e.Read(0xFE00), e.Read(0xFE9F))
+	}
+}
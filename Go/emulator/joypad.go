@@ -0,0 +1,57 @@
+package emulator
+
+import "github.com/grab-a-byte/gameboy/cpu"
+
+const JOYP_ADDRESS = 0xFF00
+
+// Button is a bit of the mask passed to SetButtons. The low nibble are the buttons and the
+// high nibble the directions, in the order they are read from JOYP.
+type Button byte
+
+const (
+	BUTTON_A Button = 1 << iota
+	BUTTON_B
+	BUTTON_SELECT
+	BUTTON_START
+	BUTTON_RIGHT
+	BUTTON_LEFT
+	BUTTON_UP
+	BUTTON_DOWN
+)
+
+// Bits of JOYP which select the buttons or directions when cleared
+const (
+	JOYP_DIRECTIONS = 0x10
+	JOYP_BUTTONS    = 0x20
+)
+
+type joypad struct {
+	selected  byte
+	pressed   Button
+	requested byte
+}
+
+// read returns JOYP, where pressed buttons in the selected groups read as 0
+func (j *joypad) read() byte {
+	value := 0xC0 | j.selected | 0x0F
+	if j.selected&JOYP_BUTTONS == 0 {
+		value &^= byte(j.pressed) & 0x0F
+	}
+	if j.selected&JOYP_DIRECTIONS == 0 {
+		value &^= byte(j.pressed) >> 4
+	}
+	return value
+}
+
+func (j *joypad) write(value byte) {
+	j.selected = value & (JOYP_BUTTONS | JOYP_DIRECTIONS)
+}
+
+// set changes which buttons are held, a new press requests the joypad interrupt which
+// also ends stop
+func (j *joypad) set(pressed Button) {
+	if pressed&^j.pressed != 0 {
+		j.requested |= cpu.INT_JOYPAD
+	}
+	j.pressed = pressed
+}
@@ -0,0 +1,375 @@
+package emulator
+
+import (
+	"image"
+	"image/color"
+	"sort"
+
+	"github.com/grab-a-byte/gameboy/cpu"
+)
+
+// Size of the screen in pixels
+const (
+	SCREEN_WIDTH  = 160
+	SCREEN_HEIGHT = 144
+)
+
+// Every line takes 456 dots, one per T-cycle, of which the first 80 search OAM and the
+// next 172 draw. Lines 144-153 are the vertical blank.
+const (
+	DOTS_PER_LINE   = 456
+	OAM_SCAN_DOTS   = 80
+	DRAWING_DOTS    = 172
+	LINES_PER_FRAME = 154
+	//T-cycles taken by each frame, for just under 60 frames a second
+	CYCLES_PER_FRAME = DOTS_PER_LINE * LINES_PER_FRAME
+)
+
+// PPU modes, as read from the low bits of STAT
+const (
+	MODE_HBLANK   = 0
+	MODE_VBLANK   = 1
+	MODE_OAM_SCAN = 2
+	MODE_DRAWING  = 3
+)
+
+// PPU registers
+const (
+	LCDC_ADDRESS = 0xFF40
+	STAT_ADDRESS = 0xFF41
+	SCY_ADDRESS  = 0xFF42
+	SCX_ADDRESS  = 0xFF43
+	LY_ADDRESS   = 0xFF44
+	LYC_ADDRESS  = 0xFF45
+	DMA_ADDRESS  = 0xFF46
+	BGP_ADDRESS  = 0xFF47
+	OBP0_ADDRESS = 0xFF48
+	OBP1_ADDRESS = 0xFF49
+	WY_ADDRESS   = 0xFF4A
+	WX_ADDRESS   = 0xFF4B
+)
+
+// Bits of LCDC
+const (
+	LCDC_BG_ENABLE     = 0x01
+	LCDC_OBJ_ENABLE    = 0x02
+	LCDC_OBJ_SIZE      = 0x04
+	LCDC_BG_MAP        = 0x08
+	LCDC_TILE_DATA     = 0x10
+	LCDC_WINDOW_ENABLE = 0x20
+	LCDC_WINDOW_MAP    = 0x40
+	LCDC_ENABLE        = 0x80
+)
+
+// Bits of STAT, the interrupt sources are ORed together and the interrupt is requested
+// when the result goes from 0 to 1
+const (
+	STAT_LYC_EQUAL = 0x04
+	STAT_HBLANK    = 0x08
+	STAT_VBLANK    = 0x10
+	STAT_OAM_SCAN  = 0x20
+	STAT_LYC       = 0x40
+)
+
+// Bits of the flags byte of an object in OAM
+const (
+	OBJ_PALETTE  = 0x10
+	OBJ_FLIP_X   = 0x20
+	OBJ_FLIP_Y   = 0x40
+	OBJ_PRIORITY = 0x80
+)
+
+// Up to 10 objects are drawn on each line, the rest are skipped
+const OBJECTS_PER_LINE = 10
+
+// Palette is the four shades of the DMG screen from lightest to darkest, the values of the framebuffer
+var Palette = color.Palette{
+	color.Gray{Y: 0xFF},
+	color.Gray{Y: 0xAA},
+	color.Gray{Y: 0x55},
+	color.Gray{Y: 0x00},
+}
+
+// ppu draws the screen a line at a time as each line finishes drawing. VRAM and OAM can
+// be accessed at any time rather than being blocked while in use.
+type ppu struct {
+	vram [0x2000]byte
+	oam  [0xA0]byte
+
+	lcdc, stat, scy, scx, ly, lyc byte
+	bgp, obp0, obp1, wy, wx       byte
+	mode                          byte
+
+	dots int
+	//The line of the window to draw next, which only counts lines the window was drawn on
+	windowLine int
+	//The STAT interrupt is requested when this goes from false to true
+	statLine  bool
+	requested byte
+
+	frame *image.Paletted
+	//Set when the last visible line has been drawn
+	frameDone bool
+}
+
+// newPPU returns a PPU in the state the boot ROM leaves it in
+func newPPU() *ppu {
+	return &ppu{
+		lcdc:  LCDC_ENABLE | LCDC_TILE_DATA | LCDC_BG_ENABLE,
+		bgp:   0xFC,
+		obp0:  0xFF,
+		obp1:  0xFF,
+		mode:  MODE_OAM_SCAN,
+		frame: image.NewPaletted(image.Rect(0, 0, SCREEN_WIDTH, SCREEN_HEIGHT), Palette),
+	}
+}
+
+func (p *ppu) enabled() bool {
+	return p.lcdc&LCDC_ENABLE != 0
+}
+
+func (p *ppu) step(cycles int) {
+	if !p.enabled() {
+		return
+	}
+	p.dots += cycles
+	for p.advance() {
+		p.updateStat()
+	}
+	p.updateStat()
+}
+
+// advance moves on to the next mode once enough dots have passed, reporting whether it did
+func (p *ppu) advance() bool {
+	switch p.mode {
+	case MODE_OAM_SCAN:
+		if p.dots < OAM_SCAN_DOTS {
+			return false
+		}
+		p.mode = MODE_DRAWING
+	case MODE_DRAWING:
+		if p.dots < OAM_SCAN_DOTS+DRAWING_DOTS {
+			return false
+		}
+		p.renderLine()
+		p.mode = MODE_HBLANK
+	case MODE_HBLANK, MODE_VBLANK:
+		if p.dots < DOTS_PER_LINE {
+			return false
+		}
+		p.dots -= DOTS_PER_LINE
+		p.ly++
+		switch {
+		case p.ly == SCREEN_HEIGHT:
+			p.mode = MODE_VBLANK
+			p.requested |= cpu.INT_VBLANK
+			p.frameDone = true
+		case p.ly == LINES_PER_FRAME:
+			p.ly = 0
+			p.windowLine = 0
+			p.mode = MODE_OAM_SCAN
+		case p.mode == MODE_HBLANK:
+			p.mode = MODE_OAM_SCAN
+		}
+	}
+	return true
+}
+
+// updateStat requests the STAT interrupt when one of its enabled sources starts
+func (p *ppu) updateStat() {
+	line := (p.stat&STAT_LYC != 0 && p.ly == p.lyc) ||
+		(p.stat&STAT_HBLANK != 0 && p.mode == MODE_HBLANK) ||
+		(p.stat&STAT_VBLANK != 0 && p.mode == MODE_VBLANK) ||
+		(p.stat&STAT_OAM_SCAN != 0 && p.mode == MODE_OAM_SCAN)
+	if line && !p.statLine {
+		p.requested |= cpu.INT_STAT
+	}
+	p.statLine = line
+}
+
+func (p *ppu) read(address uint16) byte {
+	switch address {
+	case LCDC_ADDRESS:
+		return p.lcdc
+	case STAT_ADDRESS:
+		value := 0x80 | p.stat | p.mode
+		if p.ly == p.lyc {
+			value |= STAT_LYC_EQUAL
+		}
+		return value
+	case SCY_ADDRESS:
+		return p.scy
+	case SCX_ADDRESS:
+		return p.scx
+	case LY_ADDRESS:
+		return p.ly
+	case LYC_ADDRESS:
+		return p.lyc
+	case BGP_ADDRESS:
+		return p.bgp
+	case OBP0_ADDRESS:
+		return p.obp0
+	case OBP1_ADDRESS:
+		return p.obp1
+	case WY_ADDRESS:
+		return p.wy
+	case WX_ADDRESS:
+		return p.wx
+	}
+	return 0xFF
+}
+
+func (p *ppu) write(address uint16, value byte) {
+	switch address {
+	case LCDC_ADDRESS:
+		p.setLCDC(value)
+	case STAT_ADDRESS:
+		p.stat = value & (STAT_HBLANK | STAT_VBLANK | STAT_OAM_SCAN | STAT_LYC)
+	case SCY_ADDRESS:
+		p.scy = value
+	case SCX_ADDRESS:
+		p.scx = value
+	case LYC_ADDRESS:
+		p.lyc = value
+	case BGP_ADDRESS:
+		p.bgp = value
+	case OBP0_ADDRESS:
+		p.obp0 = value
+	case OBP1_ADDRESS:
+		p.obp1 = value
+	case WY_ADDRESS:
+		p.wy = value
+	case WX_ADDRESS:
+		p.wx = value
+	}
+}
+
+// setLCDC writes LCDC, turning the screen off resets it to the start of line 0
+func (p *ppu) setLCDC(value byte) {
+	wasEnabled := p.enabled()
+	p.lcdc = value
+	switch {
+	case wasEnabled && !p.enabled():
+		p.ly, p.dots, p.windowLine = 0, 0, 0
+		p.mode = MODE_HBLANK
+	case !wasEnabled && p.enabled():
+		p.dots = 0
+		p.mode = MODE_OAM_SCAN
+	}
+}
+
+// renderLine draws line ly of the background, window and objects into the frame
+func (p *ppu) renderLine() {
+	y := int(p.ly)
+	row := p.frame.Pix[y*p.frame.Stride : y*p.frame.Stride+SCREEN_WIDTH]
+	//Colour indexes before the palette is applied, objects behind the background show through index 0
+	var background [SCREEN_WIDTH]byte
+
+	window := p.lcdc&LCDC_WINDOW_ENABLE != 0 && p.lcdc&LCDC_BG_ENABLE != 0 && y >= int(p.wy) && p.wx <= 166
+	for x := 0; x < SCREEN_WIDTH; x++ {
+		var index byte
+		switch {
+		case p.lcdc&LCDC_BG_ENABLE == 0:
+		case window && x+7 >= int(p.wx):
+			index = p.tilePixel(LCDC_WINDOW_MAP, x+7-int(p.wx), p.windowLine)
+		default:
+			index = p.tilePixel(LCDC_BG_MAP, (x+int(p.scx))&0xFF, (y+int(p.scy))&0xFF)
+		}
+		background[x] = index
+		row[x] = shade(p.bgp, index)
+	}
+	if window {
+		p.windowLine++
+	}
+
+	if p.lcdc&LCDC_OBJ_ENABLE != 0 {
+		p.renderObjects(y, row, &background)
+	}
+}
+
+// tilePixel reads the colour index at x, y of the background or window tile map
+func (p *ppu) tilePixel(mapBit byte, x, y int) byte {
+	tileMap := 0x1800
+	if p.lcdc&mapBit != 0 {
+		tileMap = 0x1C00
+	}
+	tile := p.vram[tileMap+y/8*32+x/8]
+
+	//Tiles are numbered from 0x8000, or signed from 0x9000
+	address := int(tile) * 16
+	if p.lcdc&LCDC_TILE_DATA == 0 {
+		address = 0x1000 + int(int8(tile))*16
+	}
+	address += y % 8 * 2
+	return tileRow(p.vram[address], p.vram[address+1], x%8)
+}
+
+// tileRow picks the colour index of pixel x from the two bytes making up a row of a tile
+func tileRow(low, high byte, x int) byte {
+	bit := 7 - x
+	return low>>bit&1 | (high>>bit&1)<<1
+}
+
+// shade maps a colour index through a palette register
+func shade(palette byte, index byte) byte {
+	return palette >> (index * 2) & 0x03
+}
+
+type object struct {
+	x, y  int
+	tile  byte
+	flags byte
+}
+
+// renderObjects draws the objects on line y over the background
+func (p *ppu) renderObjects(y int, row []byte, background *[SCREEN_WIDTH]byte) {
+	height := 8
+	if p.lcdc&LCDC_OBJ_SIZE != 0 {
+		height = 16
+	}
+
+	objects := make([]object, 0, OBJECTS_PER_LINE)
+	for i := 0; i < len(p.oam) && len(objects) < OBJECTS_PER_LINE; i += 4 {
+		o := object{y: int(p.oam[i]) - 16, x: int(p.oam[i+1]) - 8, tile: p.oam[i+2], flags: p.oam[i+3]}
+		if y >= o.y && y < o.y+height {
+			objects = append(objects, o)
+		}
+	}
+	//The object furthest left is drawn on top, then the first in OAM
+	sort.SliceStable(objects, func(i, j int) bool {
+		return objects[i].x < objects[j].x
+	})
+
+	for x := 0; x < SCREEN_WIDTH; x++ {
+		for _, o := range objects {
+			column := x - o.x
+			if column < 0 || column >= 8 {
+				continue
+			}
+			line := y - o.y
+			if o.flags&OBJ_FLIP_Y != 0 {
+				line = height - 1 - line
+			}
+			if o.flags&OBJ_FLIP_X != 0 {
+				column = 7 - column
+			}
+			tile := o.tile
+			if height == 16 {
+				tile &= 0xFE
+			}
+			address := int(tile)*16 + line*2
+			index := tileRow(p.vram[address], p.vram[address+1], column)
+			if index == 0 {
+				continue
+			}
+			if o.flags&OBJ_PRIORITY == 0 || background[x] == 0 {
+				palette := p.obp0
+				if o.flags&OBJ_PALETTE != 0 {
+					palette = p.obp1
+				}
+				row[x] = shade(palette, index)
+			}
+			break
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package emulator
+
+import (
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cpu"
+)
+
+// fillTile sets every pixel of a tile to colour index
+func fillTile(p *ppu, tile int, index byte) {
+	for row := 0; row < 8; row++ {
+		p.vram[tile*16+row*2] = 0xFF * (index & 1)
+		p.vram[tile*16+row*2+1] = 0xFF * (index >> 1)
+	}
+}
+
+func Test_PPUModes(t *testing.T) {
+	p := newPPU()
+	table := []struct {
+		cycles int
+		mode   byte
+		ly     byte
+	}{
+		{OAM_SCAN_DOTS - 4, MODE_OAM_SCAN, 0},
+		{4, MODE_DRAWING, 0},
+		{DRAWING_DOTS, MODE_HBLANK, 0},
+		{DOTS_PER_LINE - OAM_SCAN_DOTS - DRAWING_DOTS, MODE_OAM_SCAN, 1},
+		{DOTS_PER_LINE * 143, MODE_VBLANK, 144},
+		{DOTS_PER_LINE * 9, MODE_VBLANK, 153},
+		{DOTS_PER_LINE, MODE_OAM_SCAN, 0},
+	}
+	for i, check := range table {
+		p.step(check.cycles)
+		if p.mode != check.mode || p.ly != check.ly {
+			t.Errorf("Step %d: expected mode %d on line %d but found mode %d on line %d", i, check.mode, check.ly, p.mode, p.ly)
+		}
+		if p.mode == MODE_VBLANK && p.ly == SCREEN_HEIGHT && (p.requested&cpu.INT_VBLANK == 0 || !p.frameDone) {
+			t.Errorf("Expected the vertical blank to request an interrupt and finish the frame")
+		}
+	}
+
+	p.write(LCDC_ADDRESS, 0x00)
+	p.step(DOTS_PER_LINE)
+	if p.ly != 0 || p.read(STAT_ADDRESS)&0x03 != MODE_HBLANK {
+		t.Errorf("Expected the PPU to stop on line 0 with the LCD off but found line %d", p.ly)
+	}
+}
+
+func Test_PPUStatInterrupt(t *testing.T) {
+	p := newPPU()
+	p.write(LYC_ADDRESS, 2)
+	p.write(STAT_ADDRESS, STAT_LYC)
+	p.step(DOTS_PER_LINE * 2)
+	if p.requested&cpu.INT_STAT == 0 || p.read(STAT_ADDRESS)&STAT_LYC_EQUAL == 0 {
+		t.Errorf("Expected LY reaching LYC to request the STAT interrupt")
+	}
+
+	//The line stays high through the OAM scan so enabling it as well doesn't request again
+	p.requested = 0
+	p.write(STAT_ADDRESS, STAT_LYC|STAT_OAM_SCAN)
+	p.step(4)
+	if p.requested != 0 {
+		t.Errorf("Expected no interrupt while the STAT line stays high")
+	}
+	p.step(DOTS_PER_LINE)
+	if p.requested&cpu.INT_STAT != 0 {
+		t.Errorf("Expected no interrupt as the OAM scan of line 3 starts")
+	}
+}
+
+func Test_PPUBackgroundAndWindow(t *testing.T) {
+	p := newPPU()
+	fillTile(p, 1, 1)
+	fillTile(p, 2, 2)
+	//Tile 1 in the second column of the background, the window map is all tile 2
+	p.vram[0x1801] = 1
+	for i := 0x1C00; i < 0x2000; i++ {
+		p.vram[i] = 2
+	}
+	p.bgp = 0xE4
+	p.scx = 4
+	p.wx, p.wy = 7+80, 0
+	p.write(LCDC_ADDRESS, p.lcdc|LCDC_WINDOW_ENABLE|LCDC_WINDOW_MAP)
+	p.step(DOTS_PER_LINE)
+
+	row := p.frame.Pix[:SCREEN_WIDTH]
+	expected := map[int]byte{0: 0, 3: 0, 4: 1, 11: 1, 12: 0, 79: 0, 80: 2, 159: 2}
+	for x, pixel := range expected {
+		if row[x] != pixel {
+			t.Errorf("Expected pixel %d to be %d but found %d", x, pixel, row[x])
+		}
+	}
+	if p.windowLine != 1 {
+		t.Errorf("Expected the window to move on a line but found %d", p.windowLine)
+	}
+}
+
+func Test_PPUObjects(t *testing.T) {
+	p := newPPU()
+	p.bgp, p.obp0, p.obp1 = 0xE4, 0xE4, 0x5B
+	//Tile 1 has its top left pixel set to colour 3
+	p.vram[16], p.vram[17] = 0x80, 0x80
+	fillTile(p, 2, 1)
+	p.vram[0x1800] = 2
+	p.write(LCDC_ADDRESS, p.lcdc|LCDC_OBJ_ENABLE)
+
+	objects := [][4]byte{
+		{16, 8 + 20, 1, 0},
+		{16, 8 + 30, 1, OBJ_FLIP_X},
+		{16, 8 + 40, 1, OBJ_PALETTE},
+		//Behind the background tile at the left of the screen
+		{16, 8, 1, OBJ_PRIORITY},
+		//Drawn over the first object as it is further left
+		{16, 8 + 19, 2, 0},
+	}
+	for i, o := range objects {
+		copy(p.oam[i*4:], o[:])
+	}
+	p.step(DOTS_PER_LINE)
+
+	row := p.frame.Pix[:SCREEN_WIDTH]
+	expected := map[int]byte{0: 1, 18: 0, 19: 1, 20: 1, 27: 0, 30: 0, 37: 3, 40: 1, 41: 0}
+	for x, pixel := range expected {
+		if row[x] != pixel {
+			t.Errorf("Expected pixel %d to be %d but found %d", x, pixel, row[x])
+		}
+	}
+}
+
+func Test_PPUObjectLimit(t *testing.T) {
+	p := newPPU()
+	fillTile(p, 1, 3)
+	p.write(LCDC_ADDRESS, p.lcdc|LCDC_OBJ_ENABLE)
+	for i := 0; i < 12; i++ {
+		copy(p.oam[i*4:], []byte{16, byte(8 + i*8), 1, 0})
+	}
+	p.step(DOTS_PER_LINE)
+
+	row := p.frame.Pix[:SCREEN_WIDTH]
+	if row[79] != 3 || row[80] != 0 {
+		t.Errorf("Expected only the first 10 objects to be drawn but found %d and %d", row[79], row[80])
+	}
+}
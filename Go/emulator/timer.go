@@ -0,0 +1,79 @@
+package emulator
+
+import "github.com/grab-a-byte/gameboy/cpu"
+
+// Timer registers
+const (
+	DIV_ADDRESS  = 0xFF04
+	TIMA_ADDRESS = 0xFF05
+	TMA_ADDRESS  = 0xFF06
+	TAC_ADDRESS  = 0xFF07
+)
+
+// Bit of the internal counter which clocks TIMA for each TAC frequency, 4096, 262144,
+// 65536 and 16384 Hz. TIMA counts when the bit goes from 1 to 0.
+var timerBits = [4]uint{9, 3, 5, 7}
+
+// timer is the divider and the programmable timer, both driven by a 16 bit counter of
+// T-cycles of which DIV is the upper byte
+type timer struct {
+	counter   uint16
+	tima      byte
+	tma       byte
+	tac       byte
+	requested byte
+}
+
+// input is the signal TIMA counts the falling edges of
+func (t *timer) input() bool {
+	return t.tac&0x04 != 0 && t.counter>>timerBits[t.tac&0x03]&1 == 1
+}
+
+func (t *timer) step(cycles int) {
+	for i := 0; i < cycles; i++ {
+		before := t.input()
+		t.counter++
+		if before && !t.input() {
+			t.increment()
+		}
+	}
+}
+
+// increment counts TIMA on, reloading it from TMA and requesting an interrupt when it overflows
+func (t *timer) increment() {
+	t.tima++
+	if t.tima == 0 {
+		t.tima = t.tma
+		t.requested |= cpu.INT_TIMER
+	}
+}
+
+func (t *timer) read(address uint16) byte {
+	switch address {
+	case DIV_ADDRESS:
+		return byte(t.counter >> 8)
+	case TIMA_ADDRESS:
+		return t.tima
+	case TMA_ADDRESS:
+		return t.tma
+	}
+	return 0xF8 | t.tac
+}
+
+// write sets a timer register, resetting DIV or changing TAC can itself make a falling edge
+func (t *timer) write(address uint16, value byte) {
+	before := t.input()
+	switch address {
+	case DIV_ADDRESS:
+		t.counter = 0
+	case TIMA_ADDRESS:
+		t.tima = value
+	case TMA_ADDRESS:
+		t.tma = value
+	case TAC_ADDRESS:
+		t.tac = value & 0x07
+	}
+	if before && !t.input() {
+		t.increment()
+	}
+}
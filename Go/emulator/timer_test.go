@@ -0,0 +1,65 @@
+package emulator
+
+import (
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cpu"
+)
+
+func Test_TimerFrequencies(t *testing.T) {
+	table := []struct {
+		tac    byte
+		cycles int
+	}{
+		{0x04, 1024},
+		{0x05, 16},
+		{0x06, 64},
+		{0x07, 256},
+	}
+	for _, check := range table {
+		timer := &timer{}
+		timer.write(TAC_ADDRESS, check.tac)
+		timer.step(check.cycles - 1)
+		if timer.tima != 0 {
+			t.Errorf("TAC %02X: expected TIMA to count after %d T-cycles but it counted early", check.tac, check.cycles)
+		}
+		timer.step(1)
+		if timer.tima != 1 {
+			t.Errorf("TAC %02X: expected TIMA to count after %d T-cycles but found %d", check.tac, check.cycles, timer.tima)
+		}
+	}
+
+	timer := &timer{}
+	timer.write(TAC_ADDRESS, 0x01)
+	timer.step(1024)
+	if timer.tima != 0 || timer.read(DIV_ADDRESS) != 4 || timer.read(TAC_ADDRESS) != 0xF9 {
+		t.Errorf("Expected only DIV to count with the timer disabled")
+	}
+}
+
+func Test_TimerOverflow(t *testing.T) {
+	timer := &timer{tima: 0xFF, tma: 0x80}
+	timer.write(TAC_ADDRESS, 0x05)
+	timer.step(16)
+	if timer.tima != 0x80 || timer.requested != cpu.INT_TIMER {
+		t.Errorf("Expected TIMA to reload 80 and request the interrupt but found %02X and %02X", timer.tima, timer.requested)
+	}
+}
+
+func Test_TimerFallingEdge(t *testing.T) {
+	//Resetting DIV while the selected bit is set counts TIMA
+	timer := &timer{}
+	timer.write(TAC_ADDRESS, 0x05)
+	timer.step(8)
+	timer.write(DIV_ADDRESS, 0x12)
+	if timer.tima != 1 || timer.counter != 0 {
+		t.Errorf("Expected resetting DIV to count TIMA but found %d", timer.tima)
+	}
+
+	//So does disabling the timer
+	timer.step(8)
+	timer.write(TAC_ADDRESS, 0x00)
+	if timer.tima != 2 {
+		t.Errorf("Expected disabling the timer to count TIMA but found %d", timer.tima)
+	}
+}
@@ -1,37 +1,778 @@
+// gogb disassembles and inspects Game Boy ROMs.
+//
+//	gogb disasm [-o file] [-format text|hexdump|json|jsonl|rgbds] [-bank n] [-start addr] [-end addr] [-sym file] [rom]
+//	gogb cfg [-o file] [-format dot|json] [-function label|[bank:]address] [-sym file] [rom]
+//	gogb header [-o file] [-format text|json] [rom]
+//	gogb symbols [-o file] [-sym file] [rom]
+//	gogb verify [rom]
+//	gogb xrefs [-sym file] <rom> <[bank:]address|label>
+//	gogb fix-checksum [-o file] [rom]
+//	gogb timing [-sym file] <rom> <[bank:]address|label>
+//	gogb save-info <rom> <sav>
+//	gogb save-hexdump [-o file] <rom> <sav>
+//	gogb save-resize [-o file] [-truncate] <rom> <sav>
+//
+// The ROM is read from stdin when it is omitted or given as -, and output goes
+// to stdout unless -o is given. save-resize refuses to drop bytes past the RAM which
+// aren't a clock unless -truncate is given.
 package main
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"io"
 	"os"
-	"path"
+	"path/filepath"
+	"strings"
 
 	"github.com/grab-a-byte/gameboy/cartridge"
+	"github.com/grab-a-byte/gameboy/mbc"
 )
 
+type command struct {
+	name    string
+	summary string
+	run     func(args []string, stdin io.Reader, stdout io.Writer) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"disasm", "disassemble a ROM", disasm},
+		{"cfg", "export the control flow graph of a ROM or function", cfg},
+		{"header", "print every field of the cartridge header", header},
+		{"symbols", "write the labels of a ROM as a .sym file", symbols},
+		{"verify", "check the Nintendo logo and both checksums", verify},
+		{"fix-checksum", "rewrite the header and global checksums", fixChecksum},
+		{"xrefs", "list the references to and calls made from an address", xrefs},
+		{"timing", "estimate the cycles taken by a function", timing},
+		{"save-info", "check a .sav file against the cartridge it belongs to", saveInfo},
+		{"save-hexdump", "dump the RAM and clock of a .sav file", saveHexdump},
+		{"save-resize", "pad or truncate a .sav file to fit its cartridge", saveResize},
+	}
+}
+
+// errUsage is returned for invalid arguments, after the usage has been printed
+var errUsage = errors.New("invalid arguments")
+
 func main() {
-	dir, err := os.Getwd()
+	err := run(os.Args[1:], os.Stdin, os.Stdout)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+	case errors.Is(err, errUsage):
+		os.Exit(2)
+	default:
+		fmt.Fprintf(os.Stderr, "gogb: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		usage(os.Stderr)
+		return errUsage
+	}
+	for _, c := range commands {
+		if c.name == args[0] {
+			return c.run(args[1:], stdin, stdout)
+		}
+	}
+	if args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+		usage(stdout)
+		return nil
+	}
+	return fmt.Errorf("unknown command %q, run gogb help for a list of commands", args[0])
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: gogb <command> [options] [rom]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-14s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "The rom is read from stdin when omitted or -. Run gogb <command> -h for its options.")
+}
+
+// newFlags creates the flag set for a command, errors are returned rather than exiting
+func newFlags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: gogb %s [options] [rom]\n", name)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() > 1 {
+		fmt.Fprintf(fs.Output(), "expected a single rom but found %d\n", fs.NArg())
+		fs.Usage()
+		return errUsage
+	}
+	return nil
+}
+
+// romPath returns the rom argument, which is empty when reading from stdin
+func romPath(fs *flag.FlagSet) string {
+	if path := fs.Arg(0); path != "-" {
+		return path
+	}
+	return ""
+}
+
+func readROM(path string, stdin io.Reader) ([]byte, error) {
+	if path == "" {
+		rom, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read rom from stdin: %w", err)
+		}
+		return rom, nil
+	}
+	rom, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read rom: %w", err)
+	}
+	return rom, nil
+}
+
+func writeOutput(path string, stdout io.Writer, data []byte) error {
+	if path == "" || path == "-" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("unable to write output: %w", err)
+	}
+	return nil
+}
+
+// streamOutput calls write with the output file or stdout, for output which is written a piece at a
+// time rather than built up as a single slice first
+func streamOutput(path string, stdout io.Writer, write func(io.Writer) error) error {
+	if path == "" || path == "-" {
+		return write(stdout)
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("unable to write output: %w", err)
+	}
+	bw := bufio.NewWriter(file)
+	if err := write(bw); err != nil {
+		file.Close()
+		return err
+	}
+	if err := bw.Flush(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// load disassembles a rom, naming locations with the symbol file at path when it isn't empty
+func load(rom []byte, path string, opts ...cartridge.Option) (*cartridge.Cartridge, error) {
+	if path != "" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("unable to read symbols: %w", err)
+		}
+		defer file.Close()
+		parsed, err := cartridge.ParseSymbols(file)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		opts = append(opts, cartridge.WithSymbols(parsed))
+	}
+
+	c, err := cartridge.New(rom, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cartridge: %w", err)
+	}
+	return c, nil
+}
+
+var numberBases = map[string]cartridge.NumberBase{
+	"dec": cartridge.BASE_DECIMAL,
+	"$":   cartridge.BASE_DOLLAR,
+	"0x":  cartridge.BASE_0X,
+	"h":   cartridge.BASE_H,
+}
+
+var operandStyles = map[string]cartridge.OperandStyle{
+	"pandocs": cartridge.STYLE_PANDOCS,
+	"rgbds":   cartridge.STYLE_RGBDS,
+	"nocash":  cartridge.STYLE_NOCASH,
+}
+
+// formatFlags adds the options for how instructions are written with f as the defaults,
+// the returned function builds the formatter once the flags have been parsed
+func formatFlags(fs *flag.FlagSet, f cartridge.Formatter) func() (cartridge.Option, error) {
+	base := fs.String("base", "dec", "write numbers in `base`, dec, $, 0x or h")
+	style := fs.String("style", "pandocs", "operand `style`, pandocs for [hl+], rgbds for [hli] or nocash for ldi (hl)")
+	fs.BoolVar(&f.Uppercase, "upper", f.Uppercase, "write mnemonics and registers in upper case")
+	fs.BoolVar(&f.ExplicitA, "explicit-a", f.ExplicitA, "write the a operand of 8 bit arithmetic, as in add a, b")
+	fs.BoolVar(&f.Align, "align", f.Align, "line up operands and comments")
+	fs.BoolVar(&f.Address, "address", f.Address, "show the address column")
+	fs.BoolVar(&f.Bytes, "bytes", f.Bytes, "show the raw bytes column")
+	fs.BoolVar(&f.Cycles, "cycles", f.Cycles, "show the T and M-cycles column, taken/not taken for conditional branches")
+	fs.BoolVar(&f.Comments, "comments", f.Comments, "show the comment column")
+	return func() (cartridge.Option, error) {
+		var ok bool
+		if f.Base, ok = numberBases[*base]; !ok {
+			return nil, fmt.Errorf("unknown base %q, expected dec, $, 0x or h", *base)
+		}
+		if f.Style, ok = operandStyles[*style]; !ok {
+			return nil, fmt.Errorf("unknown style %q, expected pandocs, rgbds or nocash", *style)
+		}
+		return cartridge.WithFormatter(f), nil
+	}
+}
+
+func disasm(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlags("disasm")
+	output := fs.String("o", "", "write to `file`, or a directory for the rgbds format")
+	format := fs.String("format", "text", "output `format`, text, hexdump, json, jsonl or rgbds")
+	bank := fs.Int("bank", -1, "only list instructions in ROM `bank`")
+	start := fs.String("start", "", "only list instructions from `[bank:]address` in hex")
+	end := fs.String("end", "", "only list instructions up to and including `[bank:]address` in hex")
+	symbols := fs.String("sym", "", "name locations with the symbols in `file`")
+	formatter := formatFlags(fs, cartridge.DefaultFormatter())
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	syntax, err := formatter()
+	if err != nil {
+		return err
+	}
+
+	path := romPath(fs)
+	rom, err := readROM(path, stdin)
+	if err != nil {
+		return err
+	}
+
+	c, err := load(rom, *symbols, syntax)
+	if err != nil {
+		return err
+	}
+
+	ranged := *bank >= 0 || *start != "" || *end != ""
+	switch *format {
+	case "text":
+		if !ranged {
+			return writeOutput(*output, stdout, []byte(c.String()))
+		}
+		from, to, err := offsetRange(*bank, *start, *end, len(rom))
+		if err != nil {
+			return err
+		}
+		return writeOutput(*output, stdout, []byte(c.Listing(from, to)))
+	case "hexdump":
+		from, to, err := offsetRange(*bank, *start, *end, len(rom))
+		if err != nil {
+			return err
+		}
+		return writeOutput(*output, stdout, []byte(c.Hexdump(from, to)))
+	case "json", "jsonl":
+		if ranged {
+			return fmt.Errorf("the %s format always includes the whole rom", *format)
+		}
+		if *format == "jsonl" {
+			return streamOutput(*output, stdout, c.WriteJSONLines)
+		}
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		return writeOutput(*output, stdout, append(data, '\n'))
+	case "rgbds":
+		if ranged {
+			return fmt.Errorf("the rgbds format always includes the whole rom")
+		}
+		if *output == "" {
+			return fmt.Errorf("the rgbds format needs a directory to write to with -o")
+		}
+		name := "rom"
+		if path != "" {
+			name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		}
+		return c.RGBDS(name).Write(*output)
+	}
+	return fmt.Errorf("unknown format %q, expected text, hexdump, json, jsonl or rgbds", *format)
+}
+
+// offsetRange converts the bank and address options into a range of file offsets, end is exclusive
+func offsetRange(bank int, start, end string, size int) (int, int, error) {
+	from, to := 0, size
+	if bank >= 0 {
+		from = bank * cartridge.BANK_SIZE
+		to = min(from+cartridge.BANK_SIZE, size)
+		if from >= size {
+			return 0, 0, fmt.Errorf("bank %d is outside of the %d banks in the rom", bank, (size+cartridge.BANK_SIZE-1)/cartridge.BANK_SIZE)
+		}
+	}
+
+	offset := func(s string) (int, error) {
+		loc, err := cartridge.ParseLocation(s)
+		if err != nil {
+			return 0, err
+		}
+		//Without its own bank a switchable address is in the bank given by -bank
+		if !strings.Contains(s, ":") && loc.Bank < 0 {
+			loc.Bank = bank
+		}
+		o, ok := loc.Offset()
+		if !ok {
+			return 0, fmt.Errorf("%s is not a ROM address, switchable addresses need a bank", s)
+		}
+		return o, nil
+	}
+	if start != "" {
+		o, err := offset(start)
+		if err != nil {
+			return 0, 0, err
+		}
+		from = max(from, o)
+	}
+	if end != "" {
+		o, err := offset(end)
+		if err != nil {
+			return 0, 0, err
+		}
+		to = min(to, o+1)
+	}
+	return from, to, nil
+}
+
+func cfg(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlags("cfg")
+	output := fs.String("o", "", "write to `file`")
+	format := fs.String("format", "dot", "output `format`, dot or json")
+	function := fs.String("function", "", "only export the function starting at `label` or [bank:]address")
+	symbols := fs.String("sym", "", "name locations with the symbols in `file`")
+	formatter := formatFlags(fs, cartridge.DefaultFormatter())
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	syntax, err := formatter()
+	if err != nil {
+		return err
+	}
+
+	rom, err := readROM(romPath(fs), stdin)
+	if err != nil {
+		return err
+	}
+	c, err := load(rom, *symbols, syntax)
+	if err != nil {
+		return err
+	}
+
+	g := c.CFG()
+	if *function != "" {
+		loc, err := lookup(c, *function)
+		if err != nil {
+			return err
+		}
+		f, ok := g.Function(loc)
+		if !ok {
+			return fmt.Errorf("no function starts at %s", *function)
+		}
+		g = g.Subgraph(f)
+	}
+
+	switch *format {
+	case "dot":
+		return writeOutput(*output, stdout, []byte(g.DOT()))
+	case "json":
+		data, err := json.Marshal(g)
+		if err != nil {
+			return err
+		}
+		return writeOutput(*output, stdout, append(data, '\n'))
+	}
+	return fmt.Errorf("unknown format %q, expected dot or json", *format)
+}
+
+// lookup finds a location by its label, or parses it as a [bank:]address
+func lookup(c *cartridge.Cartridge, s string) (cartridge.Location, error) {
+	if loc, ok := c.Lookup(s); ok {
+		return loc, nil
+	}
+	loc, err := cartridge.ParseLocation(s)
+	if err != nil {
+		return cartridge.Location{}, fmt.Errorf("%s is not a label or address", s)
+	}
+	return loc, nil
+}
+
+func header(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlags("header")
+	output := fs.String("o", "", "write to `file`")
+	format := fs.String("format", "text", "output `format`, text or json")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	rom, err := readROM(romPath(fs), stdin)
+	if err != nil {
+		return err
+	}
+	h, err := cartridge.ParseHeader(rom)
 	if err != nil {
-		panic("Unable to determin current directory")
+		return err
 	}
+	switch *format {
+	case "text":
+		return writeOutput(*output, stdout, []byte(h.String()))
+	case "json":
+		data, err := json.Marshal(h)
+		if err != nil {
+			return err
+		}
+		return writeOutput(*output, stdout, append(data, '\n'))
+	}
+	return fmt.Errorf("unknown format %q, expected text or json", *format)
+}
+
+func symbols(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlags("symbols")
+	output := fs.String("o", "", "write to `file`")
+	symbols := fs.String("sym", "", "include the symbols in `file`")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	rom, err := readROM(romPath(fs), stdin)
+	if err != nil {
+		return err
+	}
+	c, err := load(rom, *symbols)
+	if err != nil {
+		return err
+	}
+	return streamOutput(*output, stdout, c.WriteSymbols)
+}
+
+func verify(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlags("verify")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	rom, err := readROM(romPath(fs), stdin)
+	if err != nil {
+		return err
+	}
+	err = cartridge.Validate(rom)
+	if errors.Is(err, cartridge.ErrFileTooSmall) {
+		return err
+	}
+
+	checks := []struct {
+		name string
+		err  error
+	}{
+		{"Nintendo logo", cartridge.ErrLogoMismatch},
+		{"Header checksum", cartridge.ErrHeaderChecksum},
+		{"Global checksum", cartridge.ErrGlobalChecksum},
+	}
+	for _, check := range checks {
+		status := "ok"
+		if errors.Is(err, check.err) {
+			status = "bad"
+		}
+		fmt.Fprintf(stdout, "%-16s %s\n", check.name+":", status)
+	}
+	return err
+}
+
+func fixChecksum(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlags("fix-checksum")
+	output := fs.String("o", "", "write to `file` instead of rewriting the rom in place")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	path := romPath(fs)
+	rom, err := readROM(path, stdin)
+	if err != nil {
+		return err
+	}
+	if err := cartridge.FixChecksums(rom); err != nil {
+		return err
+	}
+	//A rom read from a file is fixed in place, one from stdin goes to stdout
+	if *output == "" {
+		*output = path
+	}
+	return writeOutput(*output, stdout, rom)
+}
+
+func xrefs(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlags("xrefs")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gogb xrefs [options] <rom> <[bank:]address|label>")
+		fs.PrintDefaults()
+	}
+	symbols := fs.String("sym", "", "name locations with the symbols in `file`")
+	formatter := formatFlags(fs, cartridge.DefaultFormatter())
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errUsage
+	}
+	syntax, err := formatter()
+	if err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	if path == "-" {
+		path = ""
+	}
+	rom, err := readROM(path, stdin)
+	if err != nil {
+		return err
+	}
+	c, err := load(rom, *symbols, syntax)
+	if err != nil {
+		return err
+	}
+
+	loc, err := lookup(c, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	name := loc.String()
+	if label, ok := c.Label(loc); ok {
+		name = label
+	} else if register, ok := cartridge.HardwareRegister(loc.Address); ok {
+		name = register
+	}
+	sections := []struct {
+		title string
+		xrefs []cartridge.Xref
+	}{
+		{"Branches to " + name, c.BranchesTo(loc)},
+		{"Reads and writes of " + name, c.Accesses(loc)},
+		{"Calls made by " + name, c.Calls(loc)},
+	}
+
+	found := false
+	for _, section := range sections {
+		if len(section.xrefs) == 0 {
+			continue
+		}
+		if found {
+			fmt.Fprintln(stdout)
+		}
+		found = true
+		fmt.Fprintf(stdout, "%s:\n", section.title)
+		for _, x := range section.xrefs {
+			fmt.Fprintf(stdout, "  %-5s %s %s\n", x.Kind, x.Instruction.Location(), c.Format(x.Instruction))
+		}
+	}
+	if !found {
+		fmt.Fprintf(stdout, "No references to %s\n", name)
+	}
+	return nil
+}
 
-	p := path.Join(dir, "example", "example.gb")
+func timing(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := newFlags("timing")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: gogb timing [options] <rom> <[bank:]address|label>")
+		fs.PrintDefaults()
+	}
+	symbols := fs.String("sym", "", "name locations with the symbols in `file`")
+	defaults := cartridge.DefaultFormatter()
+	defaults.Bytes, defaults.Cycles, defaults.Align = true, true, true
+	formatter := formatFlags(fs, defaults)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return errUsage
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return errUsage
+	}
+	syntax, err := formatter()
+	if err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	if path == "-" {
+		path = ""
+	}
+	rom, err := readROM(path, stdin)
+	if err != nil {
+		return err
+	}
+	c, err := load(rom, *symbols, syntax)
+	if err != nil {
+		return err
+	}
+
+	loc, err := lookup(c, fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	g := c.CFG()
+	f, ok := g.Function(loc)
+	if !ok {
+		return fmt.Errorf("no function starts at %s", fs.Arg(1))
+	}
+
+	t := g.Timing(f)
+	fmt.Fprintf(stdout, "%s (%s)\n", f.Name, f.Entry)
+	fmt.Fprintf(stdout, "Shortest: %d T-cycles, %d M-cycles\n", t.Min, t.M().Min)
+	fmt.Fprintf(stdout, "Longest:  %d T-cycles, %d M-cycles\n", t.Max, t.M().Max)
+	fmt.Fprintln(stdout, "Called functions are included and loops are counted as running once.")
+	for _, b := range f.Blocks {
+		bt := b.Timing()
+		fmt.Fprintf(stdout, "\nBlock %s: %s T-cycles, %s M-cycles\n", b.Start, bt, bt.M())
+		for _, ins := range b.Instructions {
+			fmt.Fprintln(stdout, c.Line(ins))
+		}
+	}
+	return nil
+}
+
+// saveFlags creates the flag set for a command taking a rom and a .sav file
+func saveFlags(name string) *flag.FlagSet {
+	fs := newFlags(name)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: gogb %s [options] <rom> <sav>\n", name)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// readSave parses the flags of a save command then reads the header of the rom and the save
+func readSave(fs *flag.FlagSet, args []string, stdin io.Reader) (*cartridge.Header, []byte, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, nil, err
+		}
+		return nil, nil, errUsage
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return nil, nil, errUsage
+	}
+
+	path := fs.Arg(0)
+	if path == "-" {
+		path = ""
+	}
+	rom, err := readROM(path, stdin)
+	if err != nil {
+		return nil, nil, err
+	}
+	h, err := cartridge.ParseHeader(rom)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(fs.Arg(1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to read save: %w", err)
+	}
+	return h, data, nil
+}
 
-	file, err := os.Open(p)
+func saveInfo(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := saveFlags("save-info")
+	h, data, err := readSave(fs, args, stdin)
 	if err != nil {
-		panic(fmt.Sprintf("Unable to open file %s: %q", p, err))
+		return err
 	}
 
-	data, _ := io.ReadAll(file)
-	c, err := cartridge.New(data)
+	size, clock := mbc.SaveSize(h)
+	fmt.Fprintf(stdout, "%-10s %s\n", "Cartridge:", h.CartridgeType.Name())
+	fmt.Fprintf(stdout, "%-10s %d bytes\n", "RAM:", size)
+	fmt.Fprintf(stdout, "%-10s %s\n", "Clock:", yesNo(clock))
+	fmt.Fprintf(stdout, "%-10s %d bytes\n", "Save:", len(data))
+
+	save, err := mbc.ParseSave(h, data)
 	if err != nil {
-		panic("Invalid Cartridge")
+		fmt.Fprintf(stdout, "%-10s bad\n", "Status:")
+		return err
+	}
+	fmt.Fprintf(stdout, "%-10s ok\n", "Status:")
+	if clock && save.Clock == nil {
+		fmt.Fprintln(stdout, "The save has no clock, it will start from day 0")
 	}
+	return nil
+}
 
-	output, err := os.Create("example-dissassembled.txt")
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+func saveHexdump(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := saveFlags("save-hexdump")
+	output := fs.String("o", "", "write to `file`")
+	h, data, err := readSave(fs, args, stdin)
+	if err != nil {
+		return err
+	}
+	save, err := mbc.ParseSave(h, data)
+	if err != nil {
+		return err
+	}
+	return writeOutput(*output, stdout, []byte(save.Hexdump()))
+}
+
+func saveResize(args []string, stdin io.Reader, stdout io.Writer) error {
+	fs := saveFlags("save-resize")
+	output := fs.String("o", "", "write to `file` instead of rewriting the save in place")
+	truncate := fs.Bool("truncate", false, "drop bytes past the RAM which the cartridge has no clock to keep them as")
+	h, data, err := readSave(fs, args, stdin)
 	if err != nil {
-		fmt.Println(err)
+		return err
+	}
+	if !h.CartridgeType.Battery {
+		return fmt.Errorf("%w: %s", mbc.ErrNoBattery, h.CartridgeType.Name())
+	}
+
+	//Anything past the RAM the size of a clock footer is kept as the clock
+	size, clock := mbc.SaveSize(h)
+	save := &mbc.Save{RAM: data[:min(size, len(data))]}
+	extra := data[len(save.RAM):]
+	if clock && (len(extra) == mbc.RTC_FOOTER_SIZE || len(extra) == mbc.RTC_OLD_FOOTER_SIZE) {
+		save.Clock, extra = extra, nil
+	}
+	if len(extra) > 0 && !*truncate {
+		return fmt.Errorf("%w: %d bytes past the RAM are not a clock, use -truncate to drop them", mbc.ErrSaveSize, len(extra))
 	}
+	save.Resize(h, mbc.SystemClock)
 
-	output.WriteString(c.String())
+	if *output == "" {
+		*output = fs.Arg(1)
+	}
+	return writeOutput(*output, stdout, save.Bytes())
 }
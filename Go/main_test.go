@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+	"github.com/grab-a-byte/gameboy/mbc"
+)
+
+func Test_CommandsReadFilesAndStdin(t *testing.T) {
+	rom, err := os.ReadFile("example/example.gb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := run([]string{"header", "example/example.gb"}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "MBC1") {
+		t.Errorf("Expected the header to include the mapper but found\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"disasm", "-start", "0150", "-end", "0150", "-"}, bytes.NewReader(rom), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.String() != "jp_0_0150:\n00:0150 di\n" {
+		t.Errorf("Expected a single instruction but found\n%s", out.String())
+	}
+
+	if err := run([]string{"disasm", "-start", "4000", "-"}, bytes.NewReader(rom), &out); err == nil {
+		t.Errorf("Expected a switchable address without a bank to be rejected")
+	}
+	if err := run([]string{"unknown"}, nil, &out); err == nil {
+		t.Errorf("Expected an unknown command to be rejected")
+	}
+	if err := run([]string{"header", "a.gb", "b.gb"}, nil, &out); !errors.Is(err, errUsage) {
+		t.Errorf("Expected a usage error for two roms but found %v", err)
+	}
+}
+
+func Test_DisasmFormatOptions(t *testing.T) {
+	var out bytes.Buffer
+	args := []string{"disasm", "-start", "0150", "-end", "0153", "-base", "$", "-bytes", "-align", "-upper", "example/example.gb"}
+	if err := run(args, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	want := "jp_0_0150:\n00:0150 F3       DI\n00:0151 31 80 DF LD   SP, $DF80           ; WRAMX\n"
+	if out.String() != want {
+		t.Errorf("Expected\n%s\nbut found\n%s", want, out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"disasm", "-format", "hexdump", "-start", "014D", "-end", "0150", "example/example.gb"}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	want = "; Header checksum: valid\n00:014D F0" + strings.Repeat(" ", 45) + " |.|\n; Global checksum: valid\n00:014E 04 55" + strings.Repeat(" ", 42) + " |.U|\njp_0_0150:\n00:0150 F3       di\n"
+	if out.String() != want {
+		t.Errorf("Expected\n%s\nbut found\n%s", want, out.String())
+	}
+
+	if err := run([]string{"disasm", "-base", "octal", "example/example.gb"}, nil, &out); err == nil {
+		t.Errorf("Expected an unknown base to be rejected")
+	}
+}
+
+func Test_VerifyAndFixChecksum(t *testing.T) {
+	rom, err := os.ReadFile("example/example.gb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "broken.gb")
+	rom[cartridge.HEADER_CHECKSUM]++
+	if err := os.WriteFile(path, rom, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	err = run([]string{"verify", path}, nil, &out)
+	if !errors.Is(err, cartridge.ErrHeaderChecksum) {
+		t.Errorf("Expected a header checksum error but found %v", err)
+	}
+
+	if err := run([]string{"fix-checksum", path}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := run([]string{"verify", path}, nil, &out); err != nil {
+		t.Errorf("Expected the fixed rom to verify but found %v\n%s", err, out.String())
+	}
+}
+
+func Test_Xrefs(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"xrefs", "example/example.gb", "FF40"}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "Reads and writes of rLCDC:\n  write 00:0421 ldh [rLCDC], a\n") {
+		t.Errorf("Unexpected xrefs\n%s", out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"xrefs", "example/example.gb", "jp_0_0150"}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "Branches to jp_0_0150:\n  jump  00:0101 jp jp_0_0150\n") {
+		t.Errorf("Unexpected xrefs\n%s", out.String())
+	}
+
+	if err := run([]string{"xrefs", "example/example.gb"}, nil, &out); !errors.Is(err, errUsage) {
+		t.Errorf("Expected a usage error without an address but found %v", err)
+	}
+}
+
+func Test_Timing(t *testing.T) {
+	var out bytes.Buffer
+	if err := run([]string{"timing", "example/example.gb", "int_vblank"}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "int_vblank (00:0040)\nShortest: ") {
+		t.Errorf("Unexpected timing\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "\nBlock 00:0040: 16 T-cycles, 4 M-cycles\n00:0040 C3 06 18 16T 4M      jp   jp_0_1806\n") {
+		t.Errorf("Expected the first block with its cycles but found\n%s", out.String())
+	}
+	if err := run([]string{"timing", "example/example.gb", "0041"}, nil, &out); err == nil {
+		t.Errorf("Expected an address which isn't a function to be rejected")
+	}
+}
+
+func Test_SaveCommands(t *testing.T) {
+	rom, err := os.ReadFile("example/example.gb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	romPath, savePath := filepath.Join(dir, "game.gb"), filepath.Join(dir, "game.sav")
+	rom[cartridge.CARTRIDGE_TYPE], rom[cartridge.RAM_SIZE] = 0x03, 0x02
+	if err := os.WriteFile(romPath, rom, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(savePath, []byte("SAVE"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := run([]string{"save-info", romPath, savePath}, nil, &out); !errors.Is(err, mbc.ErrSaveSize) {
+		t.Errorf("Expected a size mismatch but found %v", err)
+	}
+	want := "Cartridge: MBC1+RAM+BATTERY\nRAM:       8192 bytes\nClock:     no\nSave:      4 bytes\nStatus:    bad\n"
+	if out.String() != want {
+		t.Errorf("Expected\n%s\nbut found\n%s", want, out.String())
+	}
+	if err := run([]string{"save-hexdump", romPath, savePath}, nil, &out); !errors.Is(err, mbc.ErrSaveSize) {
+		t.Errorf("Expected the hexdump to need a save of the right size but found %v", err)
+	}
+
+	if err := run([]string{"save-resize", romPath, savePath}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	long := filepath.Join(dir, "long.sav")
+	if err := os.WriteFile(long, make([]byte, 0x2000+47), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run([]string{"save-resize", romPath, long}, nil, &out); !errors.Is(err, mbc.ErrSaveSize) {
+		t.Errorf("Expected bytes past the RAM to be kept without -truncate but found %v", err)
+	}
+	if err := run([]string{"save-resize", "-truncate", romPath, long}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(long); len(data) != 0x2000 {
+		t.Errorf("Expected -truncate to drop the bytes past the RAM but found %d bytes", len(data))
+	}
+	out.Reset()
+	if err := run([]string{"save-info", romPath, savePath}, nil, &out); err != nil {
+		t.Errorf("Expected the resized save to fit but found %v\n%s", err, out.String())
+	}
+
+	out.Reset()
+	if err := run([]string{"save-hexdump", romPath, savePath}, nil, &out); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out.String(), "00:A000 53 41 56 45 00 00") || strings.Count(out.String(), "\n") != 512 {
+		t.Errorf("Unexpected hexdump\n%s", out.String()[:200])
+	}
+
+	if err := run([]string{"save-info", "example/example.gb", savePath}, nil, &out); !errors.Is(err, mbc.ErrNoBattery) {
+		t.Errorf("Expected a cartridge without a battery to be rejected but found %v", err)
+	}
+}
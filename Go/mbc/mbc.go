@@ -0,0 +1,164 @@
+// Package mbc emulates the memory bank controllers which map cartridge ROM and RAM into
+// the CPU address space, see https://gbdev.io/pandocs/MBCs.html
+package mbc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// Mapper handles the reads and writes the CPU makes to the cartridge, which are ROM at
+// 0x0000-0x7FFF and external RAM at 0xA000-0xBFFF. Writes to ROM control the mapper.
+type Mapper interface {
+	Read(address uint16) byte
+	Write(address uint16, value byte)
+}
+
+// Registers written through the ROM area, most mappers use some of these ranges
+const (
+	RAM_ENABLE_END = 0x1FFF
+	ROM_BANK_END   = 0x3FFF
+	RAM_BANK_END   = 0x5FFF
+	MODE_END       = 0x7FFF
+
+	EXTERNAL_RAM_START = 0xA000
+	EXTERNAL_RAM_END   = 0xBFFF
+)
+
+var ErrUnsupportedMapper = errors.New("unsupported mapper")
+
+// Option configures the mapper created by New
+type Option func(*options)
+
+type options struct {
+	clock Clock
+}
+
+// WithClock sets where the time comes from for cartridges with a real time clock,
+// by default it is SystemClock
+func WithClock(clock Clock) Option {
+	return func(o *options) {
+		o.clock = clock
+	}
+}
+
+// New chooses the mapper for a ROM from the cartridge type in its header, with external
+// RAM sized from the header
+func New(rom []byte, opts ...Option) (Mapper, error) {
+	o := options{clock: SystemClock}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	h, err := cartridge.ParseHeader(rom)
+	if err != nil {
+		return nil, err
+	}
+	ram := make([]byte, h.RAMSize)
+
+	t := h.CartridgeType
+	switch t.Mapper {
+	case cartridge.MAPPER_ROM_ONLY:
+		return NewROMOnly(rom, ram), nil
+	case cartridge.MAPPER_MBC1:
+		return NewMBC1(rom, ram, IsMulticart(rom)), nil
+	case cartridge.MAPPER_MBC2:
+		return NewMBC2(rom), nil
+	case cartridge.MAPPER_MBC3:
+		var rtc *RTC
+		if t.Timer {
+			rtc = NewRTC(o.clock)
+		}
+		return NewMBC3(rom, ram, rtc), nil
+	case cartridge.MAPPER_MBC5:
+		return NewMBC5(rom, ram, t.Rumble), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedMapper, t.Name())
+}
+
+// banked is the ROM and external RAM shared by every mapper
+type banked struct {
+	rom []byte
+	ram []byte
+	//Set by writes to RAM since it was last saved
+	dirty bool
+}
+
+// RAM returns the external RAM, which is what a battery keeps when the power is off
+func (b *banked) RAM() []byte {
+	return b.ram
+}
+
+// Dirty reports whether RAM has been written since it was last saved
+func (b *banked) Dirty() bool {
+	return b.dirty
+}
+
+func (b *banked) clearDirty() {
+	b.dirty = false
+}
+
+// readROM reads from a 16 KiB ROM bank, banks past the end of the ROM wrap around
+// as the unused upper bits of the bank number aren't connected
+func (b *banked) readROM(bank int, address uint16) byte {
+	banks := max(len(b.rom)/cartridge.BANK_SIZE, 1)
+	offset := bank%banks*cartridge.BANK_SIZE + int(address)%cartridge.BANK_SIZE
+	if offset >= len(b.rom) {
+		return 0xFF
+	}
+	return b.rom[offset]
+}
+
+// ramOffset finds an address of an 8 KiB RAM bank in ram, which is mirrored when smaller than the banks
+func (b *banked) ramOffset(bank int, address uint16) int {
+	return (bank*cartridge.RAM_BANK_SIZE + int(address-EXTERNAL_RAM_START)) % len(b.ram)
+}
+
+// readRAM reads from a RAM bank, reading 0xFF when there is no RAM
+func (b *banked) readRAM(bank int, address uint16) byte {
+	if len(b.ram) == 0 {
+		return 0xFF
+	}
+	return b.ram[b.ramOffset(bank, address)]
+}
+
+func (b *banked) writeRAM(bank int, address uint16, value byte) {
+	if len(b.ram) == 0 {
+		return
+	}
+	b.ram[b.ramOffset(bank, address)] = value
+	b.dirty = true
+}
+
+// ramEnabled is how most mappers decode writes to 0x0000-0x1FFF, any value with 0xA in
+// the low nibble enables RAM and anything else disables it
+func ramEnabled(value byte) bool {
+	return value&0x0F == 0x0A
+}
+
+// ROMOnly is a cartridge without a mapper, which may still have up to 8 KiB of RAM
+type ROMOnly struct {
+	banked
+}
+
+func NewROMOnly(rom []byte, ram []byte) *ROMOnly {
+	return &ROMOnly{banked{rom: rom, ram: ram}}
+}
+
+func (m *ROMOnly) Read(address uint16) byte {
+	if address <= MODE_END {
+		return m.readROM(int(address/cartridge.BANK_SIZE), address)
+	}
+	if address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END {
+		return m.readRAM(0, address)
+	}
+	return 0xFF
+}
+
+func (m *ROMOnly) Write(address uint16, value byte) {
+	if address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END {
+		m.writeRAM(0, address, value)
+	}
+}
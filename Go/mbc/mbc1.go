@@ -0,0 +1,92 @@
+package mbc
+
+import (
+	"bytes"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// MBC1 maps up to 2 MiB of ROM and 32 KiB of RAM. The ROM bank is split between a 5 bit
+// register and a 2 bit register, which in advanced banking mode also switches bank 0 and
+// the RAM bank.
+type MBC1 struct {
+	banked
+	ramEnabled bool
+	bank1      byte
+	bank2      byte
+	advanced   bool
+	//Multicarts wire only 4 bits of bank1 so that bank2 selects one of four 256 KiB games
+	multicart bool
+}
+
+func NewMBC1(rom []byte, ram []byte, multicart bool) *MBC1 {
+	return &MBC1{banked: banked{rom: rom, ram: ram}, bank1: 1, multicart: multicart}
+}
+
+// IsMulticart detects the 1 MiB MBC1 multicarts, which hold several games each with a
+// header of their own. The game in the second quarter has a Nintendo logo matching the menu's.
+func IsMulticart(rom []byte) bool {
+	if len(rom) != 64*cartridge.BANK_SIZE {
+		return false
+	}
+	logo := rom[cartridge.NINTENDO_LOGO_START : cartridge.NINTENDO_LOGO_END+1]
+	second := 0x10*cartridge.BANK_SIZE + cartridge.NINTENDO_LOGO_START
+	return bytes.Equal(rom[second:second+len(logo)], logo)
+}
+
+// upperBanks is bank2 moved to the bits of the ROM bank number it is wired to
+func (m *MBC1) upperBanks() int {
+	if m.multicart {
+		return int(m.bank2) << 4
+	}
+	return int(m.bank2) << 5
+}
+
+func (m *MBC1) romBank() int {
+	if m.multicart {
+		return m.upperBanks() | int(m.bank1&0x0F)
+	}
+	return m.upperBanks() | int(m.bank1)
+}
+
+func (m *MBC1) ramBank() int {
+	if m.advanced {
+		return int(m.bank2)
+	}
+	return 0
+}
+
+func (m *MBC1) Read(address uint16) byte {
+	switch {
+	case address <= ROM_BANK_END:
+		//Advanced mode maps the bank2 register in at 0x0000 as well
+		if m.advanced {
+			return m.readROM(m.upperBanks(), address)
+		}
+		return m.readROM(0, address)
+	case address <= MODE_END:
+		return m.readROM(m.romBank(), address)
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled:
+		return m.readRAM(m.ramBank(), address)
+	}
+	return 0xFF
+}
+
+func (m *MBC1) Write(address uint16, value byte) {
+	switch {
+	case address <= RAM_ENABLE_END:
+		m.ramEnabled = ramEnabled(value)
+	case address <= ROM_BANK_END:
+		//The zero check is on all 5 bits, so banks 0x20, 0x40 and 0x60 can't be selected
+		m.bank1 = value & 0x1F
+		if m.bank1 == 0 {
+			m.bank1 = 1
+		}
+	case address <= RAM_BANK_END:
+		m.bank2 = value & 0x03
+	case address <= MODE_END:
+		m.advanced = value&0x01 == 1
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled:
+		m.writeRAM(m.ramBank(), address, value)
+	}
+}
@@ -0,0 +1,114 @@
+package mbc
+
+import (
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+func Test_MBC1Banking(t *testing.T) {
+	m := NewMBC1(testROM(128, 0x03, 0x03), make([]byte, 4*cartridge.RAM_BANK_SIZE), false)
+
+	steps := []struct {
+		address uint16
+		value   byte
+		bank0   int
+		bank    int
+	}{
+		{0x0000, 0x00, 0, 1},
+		{0x2000, 0x00, 0, 1},
+		{0x2000, 0x05, 0, 5},
+		{0x3FFF, 0xE7, 0, 7},
+		//Only the low 5 bits are checked for 0 so 0x20 selects 1
+		{0x2000, 0x20, 0, 1},
+		{0x4000, 0x02, 0, 0x41},
+		{0x2000, 0x00, 0, 0x41},
+		{0x7FFF, 0x01, 0x40, 0x41},
+		{0x5FFF, 0x03, 0x60, 0x61},
+		{0x6000, 0x00, 0, 0x61},
+	}
+	for i, step := range steps {
+		m.Write(step.address, step.value)
+		if bank := bankAt(m, 0x0000); bank != step.bank0 {
+			t.Errorf("Step %d: expected bank %d at 0x0000 but found %d", i, step.bank0, bank)
+		}
+		if bank := bankAt(m, 0x4000); bank != step.bank {
+			t.Errorf("Step %d: expected bank %d at 0x4000 but found %d", i, step.bank, bank)
+		}
+	}
+
+	//Banks past the end of a smaller ROM wrap around
+	small := NewMBC1(testROM(8, 0x01, 0x00), nil, false)
+	small.Write(0x2000, 0x09)
+	if bank := bankAt(small, 0x4000); bank != 1 {
+		t.Errorf("Expected bank 9 of 8 to wrap to 1 but found %d", bank)
+	}
+}
+
+func Test_MBC1RAM(t *testing.T) {
+	m := NewMBC1(testROM(4, 0x03, 0x03), make([]byte, 4*cartridge.RAM_BANK_SIZE), false)
+
+	m.Write(0xA000, 0x11)
+	if v := m.Read(0xA000); v != 0xFF {
+		t.Errorf("Expected disabled RAM to read FF but found %02X", v)
+	}
+
+	m.Write(0x0000, 0x0A)
+	m.Write(0xA000, 0x11)
+	m.Write(0x4000, 0x02)
+	//Simple mode always uses RAM bank 0
+	if v := m.Read(0xA000); v != 0x11 {
+		t.Errorf("Expected RAM bank 0 in simple mode but read %02X", v)
+	}
+	m.Write(0x6000, 0x01)
+	m.Write(0xA000, 0x22)
+	m.Write(0x6000, 0x00)
+	if v := m.Read(0xA000); v != 0x11 {
+		t.Errorf("Expected RAM bank 0 to be unchanged but read %02X", v)
+	}
+	m.Write(0x6000, 0x01)
+	if v := m.Read(0xA000); v != 0x22 {
+		t.Errorf("Expected advanced mode to switch to RAM bank 2 but read %02X", v)
+	}
+
+	m.Write(0x0000, 0x00)
+	if v := m.Read(0xA000); v != 0xFF {
+		t.Errorf("Expected RAM to be disabled again but read %02X", v)
+	}
+}
+
+func Test_MBC1Multicart(t *testing.T) {
+	rom := testROM(64, 0x01, 0x00)
+	logo := []byte{0xCE, 0xED, 0x66, 0x66}
+	copy(rom[cartridge.NINTENDO_LOGO_START:], logo)
+	if IsMulticart(rom) {
+		t.Errorf("Expected a ROM with a single header not to be a multicart")
+	}
+	copy(rom[0x10*cartridge.BANK_SIZE+cartridge.NINTENDO_LOGO_START:], logo)
+	if !IsMulticart(rom) {
+		t.Fatalf("Expected a second logo at bank 0x10 to be a multicart")
+	}
+
+	m, err := New(rom)
+	if err != nil {
+		t.Fatal(err)
+	}
+	//bank1 keeps 5 bits for the zero check but only 4 reach the ROM
+	m.Write(0x2000, 0x12)
+	if bank := bankAt(m, 0x4000); bank != 0x02 {
+		t.Errorf("Expected bank 2 but found %d", bank)
+	}
+	m.Write(0x2000, 0x10)
+	if bank := bankAt(m, 0x4000); bank != 0x00 {
+		t.Errorf("Expected 0x10 to select bank 0 but found %d", bank)
+	}
+	m.Write(0x4000, 0x03)
+	m.Write(0x2000, 0x01)
+	if bank := bankAt(m, 0x4000); bank != 0x31 {
+		t.Errorf("Expected bank 0x31 but found %d", bank)
+	}
+	m.Write(0x6000, 0x01)
+	if bank := bankAt(m, 0x0000); bank != 0x30 {
+		t.Errorf("Expected advanced mode to map the game at 0x30 into 0x0000 but found %d", bank)
+	}
+}
@@ -0,0 +1,46 @@
+package mbc
+
+// Bytes of RAM built into MBC2, of which only the low 4 bits are used
+const MBC2_RAM_SIZE = 512
+
+// MBC2 maps up to 256 KiB of ROM and has 512 half bytes of RAM built in. Its registers
+// share one range, told apart by bit 8 of the address.
+type MBC2 struct {
+	banked
+	ramEnabled bool
+	bank       byte
+}
+
+func NewMBC2(rom []byte) *MBC2 {
+	return &MBC2{banked: banked{rom: rom, ram: make([]byte, MBC2_RAM_SIZE)}, bank: 1}
+}
+
+func (m *MBC2) Read(address uint16) byte {
+	switch {
+	case address <= ROM_BANK_END:
+		return m.readROM(0, address)
+	case address <= MODE_END:
+		return m.readROM(int(m.bank), address)
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled:
+		//The 512 half bytes repeat through the whole range and the upper bits read as 1
+		return 0xF0 | m.ram[int(address)%MBC2_RAM_SIZE]&0x0F
+	}
+	return 0xFF
+}
+
+func (m *MBC2) Write(address uint16, value byte) {
+	switch {
+	case address <= ROM_BANK_END:
+		if address&0x100 == 0 {
+			m.ramEnabled = ramEnabled(value)
+			return
+		}
+		m.bank = value & 0x0F
+		if m.bank == 0 {
+			m.bank = 1
+		}
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled:
+		m.ram[int(address)%MBC2_RAM_SIZE] = value & 0x0F
+		m.dirty = true
+	}
+}
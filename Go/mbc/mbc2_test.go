@@ -0,0 +1,36 @@
+package mbc
+
+import "testing"
+
+func Test_MBC2(t *testing.T) {
+	m := NewMBC2(testROM(16, 0x06, 0x00))
+
+	//Bit 8 of the address picks the register
+	m.Write(0x2000, 0x05)
+	if v := m.Read(0xA000); v != 0xFF {
+		t.Errorf("Expected a write with bit 8 clear to leave RAM disabled but read %02X", v)
+	}
+	if bank := bankAt(m, 0x4000); bank != 1 {
+		t.Errorf("Expected bank 1 but found %d", bank)
+	}
+	m.Write(0x0100, 0x05)
+	if bank := bankAt(m, 0x4000); bank != 5 {
+		t.Errorf("Expected bank 5 but found %d", bank)
+	}
+	m.Write(0x3FFF, 0xF0)
+	if bank := bankAt(m, 0x4000); bank != 1 {
+		t.Errorf("Expected bank 0 to select 1 but found %d", bank)
+	}
+
+	m.Write(0x3EFF, 0x0A)
+	m.Write(0xA001, 0xAB)
+	if v := m.Read(0xA001); v != 0xFB {
+		t.Errorf("Expected the low nibble with the upper bits set but read %02X", v)
+	}
+	if v := m.Read(0xA201); v != 0xFB {
+		t.Errorf("Expected RAM to repeat every 512 bytes but read %02X", v)
+	}
+	if v := m.Read(0xBE01); v != 0xFB {
+		t.Errorf("Expected RAM to repeat up to 0xBFFF but read %02X", v)
+	}
+}
@@ -0,0 +1,62 @@
+package mbc
+
+// MBC3 maps up to 2 MiB of ROM and 32 KiB of RAM with a 7 bit ROM bank register. Some
+// cartridges also have a real time clock whose registers replace RAM when selected.
+type MBC3 struct {
+	banked
+	ramEnabled bool
+	romBank    byte
+	ramBank    byte
+	rtc        *RTC
+}
+
+// NewMBC3 creates an MBC3, rtc is nil for cartridges without a clock
+func NewMBC3(rom []byte, ram []byte, rtc *RTC) *MBC3 {
+	return &MBC3{banked: banked{rom: rom, ram: ram}, romBank: 1, rtc: rtc}
+}
+
+// RTC returns the real time clock, or nil when the cartridge doesn't have one
+func (m *MBC3) RTC() *RTC {
+	return m.rtc
+}
+
+// clockSelected reports whether the RAM bank register selects a clock register
+func (m *MBC3) clockSelected() bool {
+	return m.rtc != nil && m.ramBank >= RTC_SECONDS && m.ramBank <= RTC_DAY_HIGH
+}
+
+func (m *MBC3) Read(address uint16) byte {
+	switch {
+	case address <= ROM_BANK_END:
+		return m.readROM(0, address)
+	case address <= MODE_END:
+		return m.readROM(int(m.romBank), address)
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled && m.clockSelected():
+		return m.rtc.Read(m.ramBank)
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled && m.ramBank <= 0x03:
+		return m.readRAM(int(m.ramBank), address)
+	}
+	return 0xFF
+}
+
+func (m *MBC3) Write(address uint16, value byte) {
+	switch {
+	case address <= RAM_ENABLE_END:
+		m.ramEnabled = ramEnabled(value)
+	case address <= ROM_BANK_END:
+		m.romBank = value & 0x7F
+		if m.romBank == 0 {
+			m.romBank = 1
+		}
+	case address <= RAM_BANK_END:
+		m.ramBank = value
+	case address <= MODE_END:
+		if m.rtc != nil {
+			m.rtc.Latch(value)
+		}
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled && m.clockSelected():
+		m.rtc.Write(m.ramBank, value)
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled && m.ramBank <= 0x03:
+		m.writeRAM(int(m.ramBank), address, value)
+	}
+}
@@ -0,0 +1,39 @@
+package mbc
+
+import (
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+func Test_MBC3(t *testing.T) {
+	m := NewMBC3(testROM(128, 0x13, 0x03), make([]byte, 4*cartridge.RAM_BANK_SIZE), nil)
+
+	steps := []struct {
+		value byte
+		bank  int
+	}{
+		{0x00, 1},
+		{0x7F, 0x7F},
+		{0x80, 1},
+		{0x25, 0x25},
+	}
+	for _, step := range steps {
+		m.Write(0x2000, step.value)
+		if bank := bankAt(m, 0x4000); bank != step.bank {
+			t.Errorf("Expected %02X to select bank %d but found %d", step.value, step.bank, bank)
+		}
+	}
+
+	m.Write(0x0000, 0x0A)
+	for bank := byte(0); bank < 4; bank++ {
+		m.Write(0x4000, bank)
+		m.Write(0xB000, 0x10+bank)
+	}
+	for bank := byte(0); bank < 4; bank++ {
+		m.Write(0x4000, bank)
+		if v := m.Read(0xB000); v != 0x10+bank {
+			t.Errorf("Expected RAM bank %d to hold %02X but read %02X", bank, 0x10+bank, v)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package mbc
+
+// Split of the MBC5 ROM bank registers, 0x2000-0x2FFF holds the low 8 bits and
+// 0x3000-0x3FFF the 9th
+const MBC5_HIGH_BANK_START = 0x3000
+
+// MBC5 maps up to 8 MiB of ROM with a 9 bit bank number, bank 0 included, and 128 KiB
+// of RAM. On rumble cartridges bit 3 of the RAM bank register drives the motor instead.
+type MBC5 struct {
+	banked
+	ramEnabled bool
+	romBank    int
+	ramBank    byte
+	rumble     bool
+	motor      bool
+}
+
+func NewMBC5(rom []byte, ram []byte, rumble bool) *MBC5 {
+	return &MBC5{banked: banked{rom: rom, ram: ram}, romBank: 1, rumble: rumble}
+}
+
+// Rumble reports whether the rumble motor is running
+func (m *MBC5) Rumble() bool {
+	return m.motor
+}
+
+func (m *MBC5) Read(address uint16) byte {
+	switch {
+	case address <= ROM_BANK_END:
+		return m.readROM(0, address)
+	case address <= MODE_END:
+		return m.readROM(m.romBank, address)
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled:
+		return m.readRAM(int(m.ramBank), address)
+	}
+	return 0xFF
+}
+
+func (m *MBC5) Write(address uint16, value byte) {
+	switch {
+	case address <= RAM_ENABLE_END:
+		//Unlike the other mappers the whole byte has to be 0x0A
+		m.ramEnabled = value == 0x0A
+	case address < MBC5_HIGH_BANK_START:
+		m.romBank = m.romBank&0x100 | int(value)
+	case address <= ROM_BANK_END:
+		m.romBank = m.romBank&0xFF | int(value&0x01)<<8
+	case address <= RAM_BANK_END:
+		m.ramBank = value & 0x0F
+		if m.rumble {
+			m.motor = value&0x08 != 0
+			m.ramBank = value & 0x07
+		}
+	case address >= EXTERNAL_RAM_START && address <= EXTERNAL_RAM_END && m.ramEnabled:
+		m.writeRAM(int(m.ramBank), address, value)
+	}
+}
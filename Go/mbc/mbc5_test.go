@@ -0,0 +1,64 @@
+package mbc
+
+import (
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+func Test_MBC5(t *testing.T) {
+	m := NewMBC5(testROM(512, 0x1B, 0x04), make([]byte, 16*cartridge.RAM_BANK_SIZE), false)
+
+	steps := []struct {
+		address uint16
+		value   byte
+		bank    int
+	}{
+		{0x2000, 0x00, 0},
+		{0x2FFF, 0xFF, 0xFF},
+		{0x3000, 0x01, 0x1FF},
+		{0x2000, 0x02, 0x102},
+		{0x3FFF, 0xFE, 0x002},
+	}
+	for i, step := range steps {
+		m.Write(step.address, step.value)
+		if bank := bankAt(m, 0x4000); bank != step.bank {
+			t.Errorf("Step %d: expected bank %d but found %d", i, step.bank, bank)
+		}
+	}
+
+	m.Write(0x0000, 0x1A)
+	m.Write(0xA000, 0x42)
+	if v := m.Read(0xA000); v != 0xFF {
+		t.Errorf("Expected only 0x0A to enable RAM but read %02X", v)
+	}
+	m.Write(0x0000, 0x0A)
+	m.Write(0x4000, 0x0F)
+	m.Write(0xA000, 0x42)
+	m.Write(0x4000, 0x00)
+	if v := m.Read(0xA000); v != 0x00 {
+		t.Errorf("Expected RAM bank 0 to be empty but read %02X", v)
+	}
+	m.Write(0x4000, 0x0F)
+	if v := m.Read(0xA000); v != 0x42 {
+		t.Errorf("Expected RAM bank 15 to hold 42 but read %02X", v)
+	}
+}
+
+func Test_MBC5Rumble(t *testing.T) {
+	m := NewMBC5(testROM(4, 0x1E, 0x03), make([]byte, 4*cartridge.RAM_BANK_SIZE), true)
+	m.Write(0x0000, 0x0A)
+
+	m.Write(0x4000, 0x09)
+	if !m.Rumble() {
+		t.Errorf("Expected bit 3 to start the motor")
+	}
+	m.Write(0xA000, 0x42)
+	m.Write(0x4000, 0x01)
+	if m.Rumble() {
+		t.Errorf("Expected clearing bit 3 to stop the motor")
+	}
+	if v := m.Read(0xA000); v != 0x42 {
+		t.Errorf("Expected the motor bit not to change the RAM bank but read %02X", v)
+	}
+}
@@ -0,0 +1,79 @@
+package mbc
+
+import (
+	"errors"
+	"math/bits"
+	"testing"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+// testROM builds a ROM of 16 KiB banks which each start with their bank number, little endian
+func testROM(banks int, cartridgeType byte, ramSize byte) []byte {
+	rom := make([]byte, banks*cartridge.BANK_SIZE)
+	for b := 0; b < banks; b++ {
+		rom[b*cartridge.BANK_SIZE] = byte(b)
+		rom[b*cartridge.BANK_SIZE+1] = byte(b >> 8)
+	}
+	rom[cartridge.CARTRIDGE_TYPE] = cartridgeType
+	rom[cartridge.ROM_SIZE] = byte(bits.Len(uint(banks)) - 2)
+	rom[cartridge.RAM_SIZE] = ramSize
+	return rom
+}
+
+// bankAt reads the number of the bank mapped in at address
+func bankAt(m Mapper, address uint16) int {
+	return int(m.Read(address)) | int(m.Read(address+1))<<8
+}
+
+func Test_New(t *testing.T) {
+	table := []struct {
+		cartridgeType byte
+		check         func(Mapper) bool
+	}{
+		{0x00, func(m Mapper) bool { _, ok := m.(*ROMOnly); return ok }},
+		{0x09, func(m Mapper) bool { _, ok := m.(*ROMOnly); return ok }},
+		{0x03, func(m Mapper) bool { _, ok := m.(*MBC1); return ok }},
+		{0x06, func(m Mapper) bool { _, ok := m.(*MBC2); return ok }},
+		{0x10, func(m Mapper) bool { _, ok := m.(*MBC3); return ok }},
+		{0x1B, func(m Mapper) bool { m5, ok := m.(*MBC5); return ok && !m5.rumble }},
+		{0x1E, func(m Mapper) bool { m5, ok := m.(*MBC5); return ok && m5.rumble }},
+	}
+	for _, check := range table {
+		m, err := New(testROM(4, check.cartridgeType, 0x02))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !check.check(m) {
+			t.Errorf("Unexpected mapper %T for cartridge type %02X", m, check.cartridgeType)
+		}
+	}
+
+	if _, err := New(testROM(4, 0xFC, 0x00)); !errors.Is(err, ErrUnsupportedMapper) {
+		t.Errorf("Expected the pocket camera to be unsupported but found %v", err)
+	}
+	if _, err := New(make([]byte, 0x100)); !errors.Is(err, cartridge.ErrFileTooSmall) {
+		t.Errorf("Expected a ROM without a header to fail but found %v", err)
+	}
+}
+
+func Test_ROMOnly(t *testing.T) {
+	m, err := New(testROM(2, 0x08, 0x02))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Write(0x2000, 0x05)
+	if bank := bankAt(m, 0x4000); bank != 1 {
+		t.Errorf("Expected bank 1 to be fixed at 0x4000 but found %d", bank)
+	}
+	m.Write(0xA123, 0x42)
+	if v := m.Read(0xA123); v != 0x42 {
+		t.Errorf("Expected RAM to be always enabled but read %02X", v)
+	}
+
+	m, _ = New(testROM(2, 0x00, 0x00))
+	m.Write(0xA000, 0x42)
+	if v := m.Read(0xA000); v != 0xFF {
+		t.Errorf("Expected missing RAM to read FF but found %02X", v)
+	}
+}
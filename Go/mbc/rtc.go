@@ -0,0 +1,237 @@
+package mbc
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Clock gives the current time to the real time clock, so that tests can control it
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is the time of the machine running the emulator
+var SystemClock Clock = systemClock{}
+
+// RTC registers, selected by writing their number to the MBC3 RAM bank register
+const (
+	RTC_SECONDS  = 0x08
+	RTC_MINUTES  = 0x09
+	RTC_HOURS    = 0x0A
+	RTC_DAY_LOW  = 0x0B
+	RTC_DAY_HIGH = 0x0C
+)
+
+// Bits of RTC_DAY_HIGH
+const (
+	RTC_DAY_BIT8  = 0x01
+	RTC_HALT      = 0x40
+	RTC_DAY_CARRY = 0x80
+)
+
+// Bytes appended to .sav files by BGB and VBA to hold the clock, 5 registers and then
+// 5 latched registers as 32 bit little endian words followed by a 64 bit UNIX timestamp.
+// Older versions of VBA wrote a 32 bit timestamp, making the footer 44 bytes.
+const (
+	RTC_FOOTER_SIZE     = 48
+	RTC_OLD_FOOTER_SIZE = 44
+)
+
+// Indexes of the registers in RTC, which are numbered from RTC_SECONDS
+const (
+	rtcSeconds = iota
+	rtcMinutes
+	rtcHours
+	rtcDayLow
+	rtcDayHigh
+)
+
+var ErrRTCFooter = errors.New("invalid RTC footer")
+
+// RTC is the real time clock of MBC3, which counts seconds, minutes, hours and 512 days
+// while the power is off. The CPU reads a copy of the counters taken when it last latched them.
+type RTC struct {
+	clock Clock
+	//Counters as of updated, kept as registers since they can be set to values the clock wouldn't reach
+	registers [5]byte
+	latched   [5]byte
+	updated   time.Time
+	//The latch is taken when 0x00 then 0x01 is written
+	latch byte
+}
+
+func NewRTC(clock Clock) *RTC {
+	return &RTC{clock: clock, updated: clock.Now(), latch: 0xFF}
+}
+
+func (r *RTC) halted() bool {
+	return r.registers[rtcDayHigh]&RTC_HALT != 0
+}
+
+// sync brings the counters up to the current time, keeping any part of a second left over
+func (r *RTC) sync() {
+	now := r.clock.Now()
+	if r.halted() || now.Before(r.updated) {
+		r.updated = now
+		return
+	}
+	elapsed := int64(now.Sub(r.updated) / time.Second)
+	r.updated = r.updated.Add(time.Duration(elapsed) * time.Second)
+	r.advance(elapsed)
+}
+
+// advance counts on by a number of seconds
+func (r *RTC) advance(seconds int64) {
+	//Out of range counters run on to the top of their bits before wrapping, without a carry
+	for ; seconds > 0 && !r.inRange(); seconds-- {
+		r.tick()
+	}
+	if seconds == 0 {
+		return
+	}
+
+	reg := &r.registers
+	days := int64(reg[rtcDayLow]) | int64(reg[rtcDayHigh]&RTC_DAY_BIT8)<<8
+	total := int64(reg[rtcSeconds]) + int64(reg[rtcMinutes])*60 + int64(reg[rtcHours])*3600 + days*86400 + seconds
+	reg[rtcSeconds] = byte(total % 60)
+	reg[rtcMinutes] = byte(total / 60 % 60)
+	reg[rtcHours] = byte(total / 3600 % 24)
+	days = total / 86400
+	high := reg[rtcDayHigh] &^ RTC_DAY_BIT8
+	if days >= 512 {
+		high |= RTC_DAY_CARRY
+		days %= 512
+	}
+	reg[rtcDayLow] = byte(days)
+	reg[rtcDayHigh] = high | byte(days>>8)
+}
+
+func (r *RTC) inRange() bool {
+	return r.registers[rtcSeconds] < 60 && r.registers[rtcMinutes] < 60 && r.registers[rtcHours] < 24
+}
+
+// tick counts on by one second
+func (r *RTC) tick() {
+	reg := &r.registers
+	if reg[rtcSeconds]++; reg[rtcSeconds] != 60 {
+		reg[rtcSeconds] &= 0x3F
+		return
+	}
+	reg[rtcSeconds] = 0
+	if reg[rtcMinutes]++; reg[rtcMinutes] != 60 {
+		reg[rtcMinutes] &= 0x3F
+		return
+	}
+	reg[rtcMinutes] = 0
+	if reg[rtcHours]++; reg[rtcHours] != 24 {
+		reg[rtcHours] &= 0x1F
+		return
+	}
+	reg[rtcHours] = 0
+	if reg[rtcDayLow]++; reg[rtcDayLow] != 0 {
+		return
+	}
+	if reg[rtcDayHigh]&RTC_DAY_BIT8 == 0 {
+		reg[rtcDayHigh] |= RTC_DAY_BIT8
+		return
+	}
+	reg[rtcDayHigh] = reg[rtcDayHigh]&^RTC_DAY_BIT8 | RTC_DAY_CARRY
+}
+
+// Latch copies the counters to the registers the CPU reads when 0x00 then 0x01 is written
+func (r *RTC) Latch(value byte) {
+	if r.latch == 0x00 && value == 0x01 {
+		r.sync()
+		r.latched = r.registers
+	}
+	r.latch = value
+}
+
+// Read returns a latched register
+func (r *RTC) Read(register byte) byte {
+	if register < RTC_SECONDS || register > RTC_DAY_HIGH {
+		return 0xFF
+	}
+	return r.latched[register-RTC_SECONDS]
+}
+
+// Write sets a counter, masked to the bits it has
+func (r *RTC) Write(register byte, value byte) {
+	if register < RTC_SECONDS || register > RTC_DAY_HIGH {
+		return
+	}
+	r.sync()
+	masks := [5]byte{0x3F, 0x3F, 0x1F, 0xFF, RTC_DAY_BIT8 | RTC_HALT | RTC_DAY_CARRY}
+	value &= masks[register-RTC_SECONDS]
+	r.registers[register-RTC_SECONDS] = value
+	r.latched[register-RTC_SECONDS] = value
+	//Writing the seconds restarts the current second
+	if register == RTC_SECONDS {
+		r.updated = r.clock.Now()
+	}
+}
+
+// String describes the running clock as days and time along with its flags
+func (r *RTC) String() string {
+	reg := r.registers
+	days := int(reg[rtcDayLow]) | int(reg[rtcDayHigh]&RTC_DAY_BIT8)<<8
+	str := fmt.Sprintf("day %d %02d:%02d:%02d", days, reg[rtcHours], reg[rtcMinutes], reg[rtcSeconds])
+	if reg[rtcDayHigh]&RTC_HALT != 0 {
+		str += ", halted"
+	}
+	if reg[rtcDayHigh]&RTC_DAY_CARRY != 0 {
+		str += ", day counter overflowed"
+	}
+	return str
+}
+
+// MarshalBinary writes the clock as the footer of a .sav file
+func (r *RTC) MarshalBinary() ([]byte, error) {
+	r.sync()
+	footer := make([]byte, RTC_FOOTER_SIZE)
+	for i := 0; i < 5; i++ {
+		binary.LittleEndian.PutUint32(footer[i*4:], uint32(r.registers[i]))
+		binary.LittleEndian.PutUint32(footer[20+i*4:], uint32(r.latched[i]))
+	}
+	binary.LittleEndian.PutUint64(footer[40:], uint64(r.updated.Unix()))
+	return footer, nil
+}
+
+// UnmarshalBinary restores the clock from the footer of a .sav file, counting on by the
+// time since it was saved
+func (r *RTC) UnmarshalBinary(footer []byte) error {
+	registers, latched, saved, err := decodeFooter(footer)
+	if err != nil {
+		return err
+	}
+	r.registers, r.latched, r.updated = registers, latched, saved
+	r.sync()
+	return nil
+}
+
+// decodeFooter reads the registers, latched registers and time of saving from a clock footer
+func decodeFooter(footer []byte) (registers, latched [5]byte, saved time.Time, err error) {
+	var timestamp int64
+	switch len(footer) {
+	case RTC_FOOTER_SIZE:
+		timestamp = int64(binary.LittleEndian.Uint64(footer[40:]))
+	case RTC_OLD_FOOTER_SIZE:
+		timestamp = int64(binary.LittleEndian.Uint32(footer[40:]))
+	default:
+		err = fmt.Errorf("%w: %d bytes but expected %d or %d", ErrRTCFooter, len(footer), RTC_FOOTER_SIZE, RTC_OLD_FOOTER_SIZE)
+		return
+	}
+	for i := 0; i < 5; i++ {
+		registers[i] = byte(binary.LittleEndian.Uint32(footer[i*4:]))
+		latched[i] = byte(binary.LittleEndian.Uint32(footer[20+i*4:]))
+	}
+	return registers, latched, time.Unix(timestamp, 0), nil
+}
@@ -0,0 +1,152 @@
+package mbc
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// testClock is a clock which only moves when told to
+type testClock struct {
+	now time.Time
+}
+
+func (c *testClock) Now() time.Time {
+	return c.now
+}
+
+func (c *testClock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+// newTestRTC returns an MBC3 with a clock starting at a fixed time and RAM enabled
+func newTestRTC() (*MBC3, *testClock) {
+	clock := &testClock{now: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)}
+	m, err := New(testROM(4, 0x10, 0x03), WithClock(clock))
+	if err != nil {
+		panic(err)
+	}
+	m.Write(0x0000, 0x0A)
+	return m.(*MBC3), clock
+}
+
+// latch takes a new copy of the clock with the 0 then 1 write
+func latch(m *MBC3) {
+	m.Write(0x6000, 0x00)
+	m.Write(0x6000, 0x01)
+}
+
+// readClock reads all five clock registers
+func readClock(m *MBC3) [5]byte {
+	var registers [5]byte
+	for i := range registers {
+		m.Write(0x4000, RTC_SECONDS+byte(i))
+		registers[i] = m.Read(0xA000)
+	}
+	return registers
+}
+
+func Test_RTCCounts(t *testing.T) {
+	m, clock := newTestRTC()
+	clock.advance(1*time.Hour + 2*time.Minute + 3*time.Second + 500*time.Millisecond)
+
+	if registers := readClock(m); registers != [5]byte{} {
+		t.Errorf("Expected the registers to hold until latched but found % X", registers)
+	}
+	latch(m)
+	if registers := readClock(m); registers != [5]byte{3, 2, 1, 0, 0} {
+		t.Errorf("Expected 1:02:03 but found % X", registers)
+	}
+
+	//The half second carries over into the next latch
+	clock.advance(500*time.Millisecond + 300*24*time.Hour)
+	latch(m)
+	if registers := readClock(m); registers != [5]byte{4, 2, 1, 300 & 0xFF, RTC_DAY_BIT8} {
+		t.Errorf("Expected day 300 at 1:02:04 but found % X", registers)
+	}
+
+	//Writing 1 again without a 0 first doesn't latch
+	clock.advance(time.Minute)
+	m.Write(0x6000, 0x01)
+	if registers := readClock(m); registers[1] != 2 {
+		t.Errorf("Expected the latch to need a 0 first but found % X", registers)
+	}
+
+	clock.advance(212 * 24 * time.Hour)
+	latch(m)
+	if registers := readClock(m); registers != [5]byte{4, 3, 1, 0, RTC_DAY_CARRY} {
+		t.Errorf("Expected day 512 to overflow into the carry but found % X", registers)
+	}
+}
+
+func Test_RTCHaltAndWrite(t *testing.T) {
+	m, clock := newTestRTC()
+
+	m.Write(0x4000, RTC_DAY_HIGH)
+	m.Write(0xA000, RTC_HALT)
+	clock.advance(time.Hour)
+	latch(m)
+	if registers := readClock(m); registers != [5]byte{0, 0, 0, 0, RTC_HALT} {
+		t.Errorf("Expected a halted clock not to count but found % X", registers)
+	}
+
+	//Counters set out of range run on to the top of their bits and wrap without a carry
+	m.Write(0x4000, RTC_SECONDS)
+	m.Write(0xA000, 0xFE)
+	m.Write(0x4000, RTC_HOURS)
+	m.Write(0xA000, 23)
+	m.Write(0x4000, RTC_DAY_HIGH)
+	m.Write(0xA000, 0x00)
+	clock.advance(3 * time.Second)
+	latch(m)
+	if registers := readClock(m); registers != [5]byte{1, 0, 23, 0, 0} {
+		t.Errorf("Expected seconds to run from 62 to 1 without a carry but found % X", registers)
+	}
+
+	m.Write(0x4000, RTC_MINUTES)
+	m.Write(0xA000, 59)
+	m.Write(0x4000, RTC_SECONDS)
+	m.Write(0xA000, 59)
+	clock.advance(time.Second)
+	latch(m)
+	if registers := readClock(m); registers != [5]byte{0, 0, 0, 1, 0} {
+		t.Errorf("Expected 23:59:59 to roll over to day 1 but found % X", registers)
+	}
+}
+
+func Test_RTCFooter(t *testing.T) {
+	m, clock := newTestRTC()
+	clock.advance(90 * time.Second)
+	latch(m)
+	clock.advance(30 * time.Second)
+
+	footer, err := m.RTC().MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(footer) != RTC_FOOTER_SIZE || footer[0] != 0 || footer[4] != 2 || footer[20] != 30 || footer[24] != 1 {
+		t.Errorf("Unexpected footer % X", footer)
+	}
+
+	//Loading counts on by the time since the save
+	restored, clock2 := newTestRTC()
+	clock2.now = clock.now.Add(time.Hour)
+	if err := restored.RTC().UnmarshalBinary(footer); err != nil {
+		t.Fatal(err)
+	}
+	if registers := readClock(restored); registers != [5]byte{30, 1, 0, 0, 0} {
+		t.Errorf("Expected the latched registers to be restored but found % X", registers)
+	}
+	latch(restored)
+	if registers := readClock(restored); registers != [5]byte{0, 2, 1, 0, 0} {
+		t.Errorf("Expected the clock to have run for the hour since saving but found % X", registers)
+	}
+
+	old := append(footer[:40:40], footer[40:44]...)
+	if err := restored.RTC().UnmarshalBinary(old); err != nil {
+		t.Errorf("Expected the 44 byte footer to load but found %v", err)
+	}
+	if err := restored.RTC().UnmarshalBinary(footer[:10]); !errors.Is(err, ErrRTCFooter) {
+		t.Errorf("Expected a short footer to fail but found %v", err)
+	}
+}
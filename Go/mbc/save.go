@@ -0,0 +1,230 @@
+package mbc
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+var (
+	ErrNoBattery = errors.New("cartridge has no battery")
+	ErrSaveSize  = errors.New("save file does not match the cartridge")
+)
+
+// SaveSize returns the bytes of RAM kept by the battery of a cartridge and whether a
+// clock footer follows it
+func SaveSize(h *cartridge.Header) (int, bool) {
+	t := h.CartridgeType
+	if !t.Battery {
+		return 0, false
+	}
+	//MBC2 RAM is built in, so the header declares none
+	if t.Mapper == cartridge.MAPPER_MBC2 {
+		return MBC2_RAM_SIZE, false
+	}
+	return h.RAMSize, t.Timer
+}
+
+// Save is the contents of a .sav file, the battery backed RAM followed by the clock on
+// cartridges which have one
+type Save struct {
+	RAM []byte
+	//The RTC footer, nil when there isn't one
+	Clock []byte
+}
+
+// ParseSave splits a .sav file into RAM and the clock, checking that its size matches
+// what the cartridge has. A clock footer may be missing, as not every emulator writes one.
+func ParseSave(h *cartridge.Header, data []byte) (*Save, error) {
+	size, clock := SaveSize(h)
+	if !h.CartridgeType.Battery {
+		return nil, fmt.Errorf("%w: %s", ErrNoBattery, h.CartridgeType.Name())
+	}
+
+	switch {
+	case len(data) == size:
+		return &Save{RAM: data}, nil
+	case clock && (len(data) == size+RTC_FOOTER_SIZE || len(data) == size+RTC_OLD_FOOTER_SIZE):
+		return &Save{RAM: data[:size], Clock: data[size:]}, nil
+	}
+	expected := fmt.Sprintf("%d bytes of RAM", size)
+	if clock {
+		expected += fmt.Sprintf(" and a %d byte clock", RTC_FOOTER_SIZE)
+	}
+	return nil, fmt.Errorf("%w: %d bytes but %s has %s", ErrSaveSize, len(data), h.CartridgeType.Name(), expected)
+}
+
+// Bytes returns the contents of the .sav file
+func (s *Save) Bytes() []byte {
+	return append(append([]byte(nil), s.RAM...), s.Clock...)
+}
+
+// Resize truncates or zero pads a save to the RAM size of a cartridge, adding a clock
+// starting from day 0 at the time of clock when the cartridge has one and the save doesn't
+func (s *Save) Resize(h *cartridge.Header, clock Clock) {
+	size, timer := SaveSize(h)
+	ram := make([]byte, size)
+	copy(ram, s.RAM)
+	s.RAM = ram
+
+	switch {
+	case !timer:
+		s.Clock = nil
+	case len(s.Clock) == 0:
+		s.Clock, _ = NewRTC(clock).MarshalBinary()
+	}
+}
+
+// Hexdump renders the RAM 16 bytes to a row with the bank and address each is seen at,
+// followed by the clock registers
+func (s *Save) Hexdump() string {
+	var builder strings.Builder
+	for offset := 0; offset < len(s.RAM); offset += 16 {
+		row := s.RAM[offset:min(offset+16, len(s.RAM))]
+		ascii := make([]byte, len(row))
+		for i, b := range row {
+			ascii[i] = '.'
+			if b >= 0x20 && b < 0x7F {
+				ascii[i] = b
+			}
+		}
+		bank, address := offset/cartridge.RAM_BANK_SIZE, EXTERNAL_RAM_START+offset%cartridge.RAM_BANK_SIZE
+		fmt.Fprintf(&builder, "%02X:%04X % -47X |%s|\n", bank, address, row, ascii)
+	}
+
+	if len(s.Clock) > 0 {
+		registers, _, saved, err := decodeFooter(s.Clock)
+		if err != nil {
+			fmt.Fprintf(&builder, "; Clock: %s\n", err)
+		} else {
+			rtc := &RTC{registers: registers}
+			fmt.Fprintf(&builder, "; Clock: %s, saved %s\n", rtc, saved.UTC().Format(time.DateTime))
+		}
+	}
+	return builder.String()
+}
+
+// battery is implemented by every mapper, giving access to the RAM to save
+type battery interface {
+	RAM() []byte
+	Dirty() bool
+	clearDirty()
+}
+
+// Battery keeps the battery backed RAM of a mapper, and its clock, in a .sav file
+type Battery struct {
+	path string
+	ram  battery
+	rtc  *RTC
+	//Save RAM written since the last save once this long has passed, 0 only saves on request
+	Interval time.Duration
+	last     time.Time
+}
+
+// OpenBattery loads the save at path into the mapper created for rom, leaving RAM as it
+// is when there is no save yet
+func OpenBattery(path string, rom []byte, m Mapper) (*Battery, error) {
+	h, err := cartridge.ParseHeader(rom)
+	if err != nil {
+		return nil, err
+	}
+	if !h.CartridgeType.Battery {
+		return nil, fmt.Errorf("%w: %s", ErrNoBattery, h.CartridgeType.Name())
+	}
+	ram, ok := m.(battery)
+	if !ok {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedMapper, m)
+	}
+	b := &Battery{path: path, ram: ram, last: time.Now()}
+	if clocked, ok := m.(interface{ RTC() *RTC }); ok {
+		b.rtc = clocked.RTC()
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return b, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to read save: %w", err)
+	}
+	save, err := ParseSave(h, data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(save.RAM) != len(ram.RAM()) {
+		return nil, fmt.Errorf("%s: %w: %d bytes but the mapper has %d", path, ErrSaveSize, len(save.RAM), len(ram.RAM()))
+	}
+	copy(ram.RAM(), save.RAM)
+	if b.rtc != nil && save.Clock != nil {
+		if err := b.rtc.UnmarshalBinary(save.Clock); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return b, nil
+}
+
+// Save writes RAM and the clock to the .sav file, replacing it only once the whole save has been written
+func (b *Battery) Save() error {
+	return b.save(time.Now())
+}
+
+func (b *Battery) save(now time.Time) error {
+	save := &Save{RAM: b.ram.RAM()}
+	if b.rtc != nil {
+		footer, err := b.rtc.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		save.Clock = footer
+	}
+	if err := writeAtomic(b.path, save.Bytes()); err != nil {
+		return fmt.Errorf("unable to write save: %w", err)
+	}
+	b.ram.clearDirty()
+	b.last = now
+	return nil
+}
+
+// Update saves when RAM has been written and Interval has passed since the last save,
+// it is meant to be called regularly such as once a frame
+func (b *Battery) Update(now time.Time) error {
+	if b.Interval == 0 || !b.ram.Dirty() || now.Sub(b.last) < b.Interval {
+		return nil
+	}
+	return b.save(now)
+}
+
+// writeAtomic writes data to a temporary file next to path then renames it over path,
+// so that a crash part way through leaves the old file in place
+func writeAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmp := file.Name()
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
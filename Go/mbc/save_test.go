@@ -0,0 +1,186 @@
+package mbc
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/grab-a-byte/gameboy/cartridge"
+)
+
+func testHeader(t *testing.T, cartridgeType byte, ramSize byte) *cartridge.Header {
+	h, err := cartridge.ParseHeader(testROM(4, cartridgeType, ramSize))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func Test_ParseSave(t *testing.T) {
+	table := []struct {
+		cartridgeType byte
+		ramSize       byte
+		length        int
+		ram           int
+		clock         int
+		err           error
+	}{
+		{0x03, 0x02, 0x2000, 0x2000, 0, nil},
+		{0x03, 0x03, 0x2000, 0, 0, ErrSaveSize},
+		{0x06, 0x00, MBC2_RAM_SIZE, MBC2_RAM_SIZE, 0, nil},
+		{0x10, 0x03, 0x8000 + RTC_FOOTER_SIZE, 0x8000, RTC_FOOTER_SIZE, nil},
+		{0x10, 0x03, 0x8000 + RTC_OLD_FOOTER_SIZE, 0x8000, RTC_OLD_FOOTER_SIZE, nil},
+		{0x10, 0x03, 0x8000, 0x8000, 0, nil},
+		{0x0F, 0x00, RTC_FOOTER_SIZE, 0, RTC_FOOTER_SIZE, nil},
+		{0x13, 0x03, 0x8000 + RTC_FOOTER_SIZE, 0, 0, ErrSaveSize},
+		{0x01, 0x00, 0, 0, 0, ErrNoBattery},
+	}
+	for _, check := range table {
+		save, err := ParseSave(testHeader(t, check.cartridgeType, check.ramSize), make([]byte, check.length))
+		if !errors.Is(err, check.err) {
+			t.Errorf("%02X with %d bytes: expected %v but found %v", check.cartridgeType, check.length, check.err, err)
+			continue
+		}
+		if err == nil && (len(save.RAM) != check.ram || len(save.Clock) != check.clock) {
+			t.Errorf("%02X with %d bytes: expected %d bytes of RAM and %d of clock but found %d and %d", check.cartridgeType, check.length, check.ram, check.clock, len(save.RAM), len(save.Clock))
+		}
+	}
+
+	_, err := ParseSave(testHeader(t, 0x10, 0x03), make([]byte, 100))
+	expected := "save file does not match the cartridge: 100 bytes but MBC3+TIMER+RAM+BATTERY has 32768 bytes of RAM and a 48 byte clock"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected %q but found %v", expected, err)
+	}
+}
+
+func Test_SaveResize(t *testing.T) {
+	clock := &testClock{now: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)}
+	save := &Save{RAM: []byte{1, 2, 3}}
+	save.Resize(testHeader(t, 0x10, 0x02), clock)
+	if len(save.RAM) != 0x2000 || save.RAM[2] != 3 || len(save.Clock) != RTC_FOOTER_SIZE {
+		t.Errorf("Expected RAM to be padded and a clock added but found %d and %d bytes", len(save.RAM), len(save.Clock))
+	}
+	registers, _, saved, err := decodeFooter(save.Clock)
+	if err != nil || registers != [5]byte{} || !saved.Equal(clock.now) {
+		t.Errorf("Expected a clock at day 0 saved at %s but found %v saved at %s, %v", clock.now, registers, saved, err)
+	}
+	save.Resize(testHeader(t, 0x06, 0x00), clock)
+	if len(save.RAM) != MBC2_RAM_SIZE || save.Clock != nil {
+		t.Errorf("Expected RAM to be truncated and the clock dropped but found %d and %d bytes", len(save.RAM), len(save.Clock))
+	}
+}
+
+func Test_SaveHexdump(t *testing.T) {
+	ram := make([]byte, 0x2010)
+	copy(ram, "HELLO")
+	ram[0x2000] = 0xFF
+	rtc := NewRTC(&testClock{now: time.Date(2001, 1, 1, 0, 0, 0, 0, time.UTC)})
+	rtc.registers = [5]byte{5, 4, 3, 0x2C, RTC_DAY_BIT8 | RTC_HALT}
+	footer, _ := rtc.MarshalBinary()
+
+	lines := strings.Split((&Save{RAM: ram, Clock: footer}).Hexdump(), "\n")
+	expected := []string{
+		"00:A000 48 45 4C 4C 4F 00 00 00 00 00 00 00 00 00 00 00 |HELLO...........|",
+		"00:A010 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 |................|",
+	}
+	for i, line := range expected {
+		if lines[i] != line {
+			t.Errorf("Expected %q but found %q", line, lines[i])
+		}
+	}
+	if line := lines[len(lines)-3]; line != "01:A000 FF 00 00 00 00 00 00 00 00 00 00 00 00 00 00 00 |................|" {
+		t.Errorf("Expected the second bank to start at A000 but found %q", line)
+	}
+	if line := lines[len(lines)-2]; line != "; Clock: day 300 03:04:05, halted, saved 2001-01-01 00:00:00" {
+		t.Errorf("Unexpected clock %q", line)
+	}
+}
+
+func Test_Battery(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "game.sav")
+	rom := testROM(4, 0x03, 0x02)
+
+	m, _ := New(rom)
+	b, err := OpenBattery(path, rom, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.Interval = time.Second
+
+	m.Write(0x0000, 0x0A)
+	m.Write(0xA000, 0x42)
+	if err := b.Update(time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected nothing to be saved before the interval")
+	}
+	if err := b.Update(time.Now().Add(2 * time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 0x2000 || data[0] != 0x42 {
+		t.Errorf("Expected 8 KiB starting 42 but found %d bytes starting %02X", len(data), data[0])
+	}
+	if m.(*MBC1).Dirty() {
+		t.Errorf("Expected saving to clear the dirty flag")
+	}
+
+	m.Write(0xA001, 0x43)
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected only the save to be left in the directory but found %d files", len(entries))
+	}
+
+	reloaded, _ := New(rom)
+	if _, err := OpenBattery(path, rom, reloaded); err != nil {
+		t.Fatal(err)
+	}
+	reloaded.Write(0x0000, 0x0A)
+	if reloaded.Read(0xA000) != 0x42 || reloaded.Read(0xA001) != 0x43 {
+		t.Errorf("Expected the save to be loaded into RAM")
+	}
+
+	os.WriteFile(path, make([]byte, 100), 0o644)
+	if _, err := OpenBattery(path, rom, reloaded); !errors.Is(err, ErrSaveSize) {
+		t.Errorf("Expected a save of the wrong size to fail but found %v", err)
+	}
+	if _, err := OpenBattery(path, testROM(4, 0x01, 0x00), reloaded); !errors.Is(err, ErrNoBattery) {
+		t.Errorf("Expected a cartridge without a battery to fail but found %v", err)
+	}
+}
+
+func Test_BatteryClock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clock.sav")
+	rom := testROM(4, 0x10, 0x03)
+
+	m, clock := newTestRTC()
+	b, err := OpenBattery(path, rom, m)
+	if err != nil {
+		t.Fatal(err)
+	}
+	clock.advance(time.Hour)
+	if err := b.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded, later := newTestRTC()
+	later.now = clock.now.Add(time.Minute)
+	if _, err := OpenBattery(path, rom, reloaded); err != nil {
+		t.Fatal(err)
+	}
+	latch(reloaded)
+	if registers := readClock(reloaded); registers != [5]byte{0, 1, 1, 0, 0} {
+		t.Errorf("Expected the clock to be restored and run on but found % X", registers)
+	}
+}
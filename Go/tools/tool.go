@@ -1,15 +1,246 @@
+// tools generates the decoder lookup tables in cartridge/opcodes_table.go from the
+// opcode reference in cartridge/opcodes.txt. It is run by go generate in the cartridge
+// package.
 package main
 
-import "fmt"
+import (
+	"bufio"
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// spec is one line of the opcode reference
+type spec struct {
+	prefixed     bool
+	code         byte
+	mnemonic     string
+	length       int
+	cycles       int
+	branchCycles int
+}
+
+var registers = map[string]string{
+	"a":   "REG_A",
+	"b":   "REG_B",
+	"c":   "REG_C",
+	"d":   "REG_D",
+	"e":   "REG_E",
+	"h":   "REG_H",
+	"l":   "REG_L",
+	"af":  "REG_AF",
+	"bc":  "REG_BC",
+	"de":  "REG_DE",
+	"hl":  "REG_HL",
+	"sp":  "REG_SP",
+	"hl+": "REG_HLI",
+	"hl-": "REG_HLD",
+}
+
+var conditions = map[string]string{
+	"nz": "COND_NZ",
+	"z":  "COND_Z",
+	"nc": "COND_NC",
+	"c":  "COND_C",
+}
 
 func main() {
-	value := 0b11000000
-	maxValue := 0b11111111
-	prefix := "0x"
-	for i := value; i <= maxValue; i++ {
-		fmt.Printf("%s%02x,", prefix, i)
-		if i%10 == 0 {
-			fmt.Println()
+	specPath := flag.String("spec", "opcodes.txt", "opcode reference to read")
+	outPath := flag.String("out", "opcodes_table.go", "Go file to write")
+	flag.Parse()
+
+	in, err := os.Open(*specPath)
+	if err != nil {
+		fail(err)
+	}
+	defer in.Close()
+
+	src, err := generate(in)
+	if err != nil {
+		fail(fmt.Errorf("%s: %w", *specPath, err))
+	}
+	if err := os.WriteFile(*outPath, src, 0644); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "tools:", err)
+	os.Exit(1)
+}
+
+// generate reads the opcode reference and returns the formatted source of the tables
+func generate(r io.Reader) ([]byte, error) {
+	var base, prefixed [256]*spec
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		s, err := parseSpec(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		table := &base
+		if s.prefixed {
+			table = &prefixed
+		}
+		if table[s.code] != nil {
+			return nil, fmt.Errorf("line %d: opcode %02X is listed twice", line, s.code)
+		}
+		table[s.code] = s
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	out.WriteString("// Code generated by go run ../tools from opcodes.txt; DO NOT EDIT.\n\n")
+	out.WriteString("package cartridge\n\n")
+	for _, t := range []struct {
+		name  string
+		doc   string
+		specs *[256]*spec
+	}{
+		{"baseOpcodes", "baseOpcodes decodes the first byte of an instruction", &base},
+		{"prefixedOpcodes", "prefixedOpcodes decodes the byte following 0xCB", &prefixed},
+	} {
+		fmt.Fprintf(&out, "// %s\nvar %s = [256]opcodeSpec{\n", t.doc, t.name)
+		for code, s := range t.specs {
+			if s == nil {
+				return nil, fmt.Errorf("%s is missing opcode %02X", t.name, code)
+			}
+			entry, err := s.goSource()
+			if err != nil {
+				return nil, fmt.Errorf("opcode %02X: %w", code, err)
+			}
+			fmt.Fprintf(&out, "\t// %s\n\t0x%02X: %s,\n", s.mnemonic, code, entry)
+		}
+		out.WriteString("}\n\n")
+	}
+	return format.Source(out.Bytes())
+}
+
+// parseSpec parses a tab separated opcode, length, cycles, flags and mnemonic
+func parseSpec(line string) (*spec, error) {
+	fields := strings.Split(line, "\t")
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("expected 5 fields but found %d", len(fields))
+	}
+
+	s := &spec{mnemonic: fields[4]}
+	code := fields[0]
+	if len(code) == 4 && strings.HasPrefix(code, "CB") {
+		s.prefixed = true
+		code = code[2:]
+	}
+	value, err := strconv.ParseUint(code, 16, 8)
+	if err != nil {
+		return nil, fmt.Errorf("invalid opcode %q", fields[0])
+	}
+	s.code = byte(value)
+
+	if s.length, err = strconv.Atoi(fields[1]); err != nil {
+		return nil, fmt.Errorf("invalid length %q", fields[1])
+	}
+	//Conditional instructions list the taken cost then the not taken cost
+	taken, notTaken, conditional := strings.Cut(fields[2], "/")
+	if s.cycles, err = strconv.Atoi(taken); err != nil {
+		return nil, fmt.Errorf("invalid cycles %q", fields[2])
+	}
+	if conditional {
+		s.branchCycles = s.cycles
+		if s.cycles, err = strconv.Atoi(notTaken); err != nil {
+			return nil, fmt.Errorf("invalid cycles %q", fields[2])
+		}
+	}
+	return s, nil
+}
+
+// goSource renders the spec as an opcodeSpec literal
+func (s *spec) goSource() (string, error) {
+	name, args, _ := strings.Cut(s.mnemonic, " ")
+	opcode := "OP_" + strings.ToUpper(name)
+	//The 0xCB prefix is decoded through prefixedOpcodes
+	if name == "-" || name == "prefix" {
+		opcode = "OP_INVALID"
+	}
+
+	fields := []string{"opcode: " + opcode}
+	if args != "" {
+		operands := []string{}
+		for i, arg := range strings.Split(args, ", ") {
+			operand, err := operandSource(name, i, arg)
+			if err != nil {
+				return "", err
+			}
+			operands = append(operands, operand)
+		}
+		fields = append(fields, "operands: []operandSpec{"+strings.Join(operands, ", ")+"}")
+	}
+	fields = append(fields, fmt.Sprintf("length: %d", s.length), fmt.Sprintf("cycles: %d", s.cycles))
+	if s.branchCycles != 0 {
+		fields = append(fields, fmt.Sprintf("branchCycles: %d", s.branchCycles))
+	}
+	return "{" + strings.Join(fields, ", ") + "}", nil
+}
+
+// operandSource renders an operand of the mnemonic as an operandSpec literal
+func operandSource(mnemonic string, index int, arg string) (string, error) {
+	spec := func(operand string, encoding string) string {
+		return "{Operand{" + operand + "}, " + encoding + "}"
+	}
+
+	inner, indirect := strings.CutPrefix(arg, "[")
+	if indirect {
+		inner = strings.TrimSuffix(inner, "]")
+	}
+	indirection := ""
+	if indirect {
+		indirection = ", Indirect: true"
+	}
+
+	switch mnemonic {
+	case "jr", "jp", "call", "ret":
+		if c, ok := conditions[arg]; ok && index == 0 {
+			return spec("Kind: OPERAND_COND, Cond: "+c, "ENCODING_NONE"), nil
+		}
+	case "bit", "res", "set":
+		if index == 0 {
+			return spec("Kind: OPERAND_BIT, Value: "+arg, "ENCODING_NONE"), nil
+		}
+	case "rst":
+		vector, err := strconv.ParseUint(strings.TrimPrefix(arg, "$"), 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("invalid rst vector %q", arg)
+		}
+		return spec(fmt.Sprintf("Kind: OPERAND_VECTOR, Value: 0x%02X", vector), "ENCODING_NONE"), nil
+	}
+
+	if r, ok := registers[inner]; ok {
+		return spec("Kind: OPERAND_REG, Register: "+r+indirection, "ENCODING_NONE"), nil
+	}
+	switch inner {
+	case "n8", "a8":
+		return spec("Kind: OPERAND_IMM8"+indirection, "ENCODING_U8"), nil
+	case "n16", "a16":
+		return spec("Kind: OPERAND_IMM16"+indirection, "ENCODING_U16"), nil
+	case "e8":
+		//jr offsets are relative to the next instruction, add sp adds them to sp
+		if mnemonic == "jr" {
+			return spec("Kind: OPERAND_OFFSET", "ENCODING_S8"), nil
 		}
+		return spec("Kind: OPERAND_SIMM8", "ENCODING_S8"), nil
+	case "sp + e8":
+		return spec("Kind: OPERAND_SP_OFFSET", "ENCODING_S8"), nil
 	}
+	return "", fmt.Errorf("unknown operand %q", arg)
 }
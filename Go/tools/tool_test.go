@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+)
+
+// The checked in tables have to be regenerated whenever opcodes.txt changes
+func Test_GeneratedTablesAreUpToDate(t *testing.T) {
+	spec, err := os.Open("../cartridge/opcodes.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer spec.Close()
+
+	want, err := generate(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile("../cartridge/opcodes_table.go")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Error("cartridge/opcodes_table.go is out of date, run go generate ./cartridge")
+	}
+}
+
+func Test_GenerateRejectsBadSpecs(t *testing.T) {
+	table := []struct {
+		spec string
+		err  string
+	}{
+		{"00\t1\t4\t----\tnop\n00\t1\t4\t----\tnop", "listed twice"},
+		{"00\t1\t4\t----", "expected 5 fields"},
+		{"00\tx\t4\t----\tnop", "invalid length"},
+		{"00\t1\t4\t----\tld q, a", "unknown operand"},
+		{"00\t1\t4\t----\tnop", "missing opcode 01"},
+	}
+	for _, check := range table {
+		_, err := generate(strings.NewReader(check.spec))
+		if err == nil || !strings.Contains(err.Error(), check.err) {
+			t.Errorf("Expected error containing %q for %q but found %v", check.err, check.spec, err)
+		}
+	}
+}